@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/backtest"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+)
+
+func main() {
+	var (
+		policyPath  = flag.String("policy", "deploy/policies/checkout.yaml", "policy to backtest")
+		comparePath = flag.String("compare", "", "second policy to compare against")
+		input       = flag.String("input", "", "recorded telemetry file (length-delimited protobuf or JSONL)")
+		format      = flag.String("format", "", "input format: pb or jsonl (default: from file extension)")
+		brokers     = flag.String("brokers", "localhost:9092", "Kafka brokers, comma separated")
+		topic       = flag.String("topic", "telemetry.raw", "Kafka telemetry topic")
+		partition   = flag.Int("partition", 0, "Kafka partition to replay")
+		startOffset = flag.Int64("start-offset", -1, "first Kafka offset to replay (inclusive)")
+		endOffset   = flag.Int64("end-offset", -1, "last Kafka offset to replay (exclusive)")
+	)
+	flag.Parse()
+
+	policy := mustLoadPolicy(*policyPath)
+
+	var (
+		events []decision.Telemetry
+		err    error
+	)
+	switch {
+	case *input != "":
+		events, err = backtest.ReadFile(*input, *format)
+	case *startOffset >= 0 && *endOffset > *startOffset:
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		events, err = backtest.ReadKafka(ctx, strings.Split(*brokers, ","), *topic,
+			*partition, *startOffset, *endOffset)
+	default:
+		log.Fatalf("either -input or a -start-offset/-end-offset range is required")
+	}
+	if err != nil {
+		log.Fatalf("failed to read telemetry: %v", err)
+	}
+
+	results := backtest.Replay(policy, events)
+	fmt.Printf("policy %s (service=%s window=%ds), %d events\n\n",
+		*policyPath, policy.Service, policy.WindowSeconds, len(events))
+	printTimeline(results)
+	printSummary(*policyPath, backtest.Summarize(results))
+
+	if *comparePath == "" {
+		return
+	}
+
+	other := mustLoadPolicy(*comparePath)
+	if other.Service != policy.Service {
+		log.Fatalf("cannot compare policies for different services: %q vs %q",
+			policy.Service, other.Service)
+	}
+
+	otherResults := backtest.Replay(other, events)
+	printSummary(*comparePath, backtest.Summarize(otherResults))
+
+	diffs := backtest.Compare(results, otherResults)
+	fmt.Printf("\n%d aligned windows differ\n", len(diffs))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, d := range diffs {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", d.Start.UTC().Format(time.RFC3339), d.A, d.B)
+	}
+	w.Flush()
+}
+
+func mustLoadPolicy(path string) *decision.Policy {
+	policy, err := decision.LoadPolicy(path)
+	if err == nil {
+		err = policy.Validate()
+	}
+	if err != nil {
+		log.Fatalf("failed to load policy %s: %v", path, err)
+	}
+	return policy
+}
+
+func printTimeline(results []backtest.WindowResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "WINDOW START\tEVENTS\tDECISION\tREASON")
+	for _, r := range results {
+		d := string(r.Decision)
+		if r.Pending {
+			d = "PENDING"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", r.Start.UTC().Format(time.RFC3339), r.Events, d, r.Reason)
+	}
+	w.Flush()
+}
+
+func printSummary(name string, s backtest.Summary) {
+	fmt.Printf("\n%s: %d windows, PROMOTE=%d PAUSE=%d ROLLBACK=%d",
+		name, s.Windows,
+		s.Counts[decision.Promote], s.Counts[decision.Pause], s.Counts[decision.Rollback])
+	if s.FirstRollback != nil {
+		fmt.Printf(", first ROLLBACK at %s", s.FirstRollback.UTC().Format(time.RFC3339))
+	}
+	fmt.Println()
+}
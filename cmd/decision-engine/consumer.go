@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/backtest"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const checkpointName = "decision-engine"
+
+// consumer routes telemetry to the service loops. Offsets are committed
+// to Kafka only after a checkpoint holding the open windows that cover
+// them has been saved, so a restarted or newly elected engine restores
+// those windows and resumes right after them.
+type consumer struct {
+	reader   *kafka.Reader
+	registry *registry
+	store    storage.CheckpointStore
+	lead     *leader
+	interval time.Duration
+
+	// offsets is the next offset to route, per partition; messages
+	// before it are already in a restored window.
+	offsets map[int]int64
+	// uncommitted is the newest routed message per partition since the
+	// last commit.
+	uncommitted map[int]kafka.Message
+}
+
+func newConsumer(registry *registry, store storage.CheckpointStore, lead *leader, interval time.Duration) *consumer {
+	return &consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{broker},
+			Topic:   telemetryTopic,
+			GroupID: consumerGroup,
+		}),
+		registry:    registry,
+		store:       store,
+		lead:        lead,
+		interval:    interval,
+		offsets:     make(map[int]int64),
+		uncommitted: make(map[int]kafka.Message),
+	}
+}
+
+// run consumes until ctx is cancelled.
+func (c *consumer) run(ctx context.Context) {
+	defer c.reader.Close()
+
+	if err := c.restore(ctx); err != nil {
+		log.Printf("failed to restore checkpoint, starting from committed offsets: %v", err)
+	}
+
+	msgs := make(chan kafka.Message)
+	go func() {
+		for {
+			msg, err := c.reader.FetchMessage(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Printf("kafka read error: %v", err)
+				continue
+			}
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-msgs:
+			c.route(msg)
+		case <-ticker.C:
+			if err := c.checkpoint(ctx); err != nil {
+				log.Printf("checkpoint failed: %v", err)
+			}
+		}
+	}
+}
+
+func (c *consumer) route(msg kafka.Message) {
+	if next, ok := c.offsets[msg.Partition]; ok && msg.Offset < next {
+		return
+	}
+	c.offsets[msg.Partition] = msg.Offset + 1
+	c.uncommitted[msg.Partition] = msg
+
+	var te rolloutpb.TelemetryEvent
+	if err := proto.Unmarshal(msg.Value, &te); err != nil {
+		log.Printf("invalid telemetry payload")
+		return
+	}
+
+	key := storage.Key(te.Tenant, te.ServiceId)
+	loop, ok := c.registry.get(key)
+	if !ok {
+		log.Printf("no policy for service=%q, dropping telemetry", key)
+		return
+	}
+
+	loop.send(backtest.FromProto(&te))
+}
+
+// checkpoint saves every open window with the offsets routed so far,
+// then commits those offsets.
+func (c *consumer) checkpoint(ctx context.Context) error {
+	fenced, leading := c.lead.fence(ctx)
+	if !leading {
+		return storage.ErrFenced
+	}
+
+	cp := &storage.Checkpoint{
+		Offsets: make(map[int]int64, len(c.offsets)),
+		Windows: make(map[string]json.RawMessage),
+		SavedAt: time.Now().UnixMilli(),
+	}
+	for p, off := range c.offsets {
+		cp.Offsets[p] = off
+	}
+	for key, events := range c.registry.windows() {
+		raw, err := json.Marshal(events)
+		if err != nil {
+			return err
+		}
+		cp.Windows[key] = raw
+	}
+
+	if err := c.store.SaveCheckpoint(fenced, checkpointName, cp); err != nil {
+		return err
+	}
+
+	if len(c.uncommitted) == 0 {
+		return nil
+	}
+	commit := make([]kafka.Message, 0, len(c.uncommitted))
+	for _, msg := range c.uncommitted {
+		commit = append(commit, msg)
+	}
+	if err := c.reader.CommitMessages(ctx, commit...); err != nil {
+		return err
+	}
+	c.uncommitted = make(map[int]kafka.Message)
+	return nil
+}
+
+// restore refills the service windows from the last checkpoint. Loops
+// must already be running.
+func (c *consumer) restore(ctx context.Context) error {
+	cp, err := c.store.LoadCheckpoint(ctx, checkpointName)
+	if err != nil || cp == nil {
+		return err
+	}
+
+	for p, off := range cp.Offsets {
+		c.offsets[p] = off
+	}
+
+	restored := 0
+	for key, raw := range cp.Windows {
+		var events []decision.Telemetry
+		if err := json.Unmarshal(raw, &events); err != nil {
+			return err
+		}
+		loop, ok := c.registry.get(key)
+		if !ok {
+			continue
+		}
+		for _, ev := range events {
+			loop.send(ev)
+		}
+		restored += len(events)
+	}
+
+	log.Printf("restored checkpoint from %s: %d windows, %d events",
+		time.UnixMilli(cp.SavedAt).Format(time.RFC3339), len(cp.Windows), restored)
+	return nil
+}
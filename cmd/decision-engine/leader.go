@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const leaseName = "decision-engine"
+
+// leader campaigns for the engine-wide lease. Only the holder consumes
+// telemetry and evaluates windows; a standby takes over at most one ttl
+// plus one renewal interval after the leader stops renewing. State
+// writes carry the lease's fencing token, so a deposed leader that has
+// not noticed yet cannot overwrite its successor's decisions.
+type leader struct {
+	store  storage.LeaseStore
+	holder string
+	ttl    time.Duration
+	token  atomic.Int64
+}
+
+func newLeader(store storage.LeaseStore, holder string, ttl time.Duration) *leader {
+	return &leader{store: store, holder: holder, ttl: ttl}
+}
+
+// lead starts leading under token. The returned func ends it and waits
+// for elected to return, so a new term never overlaps the previous one.
+func (l *leader) lead(ctx context.Context, token int64, elected func(ctx context.Context)) func() {
+	l.token.Store(token)
+	leadCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	log.Printf("elected leader holder=%s token=%d", l.holder, token)
+
+	go func() {
+		defer close(done)
+		elected(leadCtx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// fence returns ctx marked with the current fencing token, and false if
+// this instance is not the leader.
+func (l *leader) fence(ctx context.Context) (context.Context, bool) {
+	token := l.token.Load()
+	if token == 0 {
+		return ctx, false
+	}
+	return storage.WithFence(ctx, leaseName, token), true
+}
+
+// run campaigns until ctx is done, calling elected with a context that
+// is cancelled on demotion. elected should return once it is.
+func (l *leader) run(ctx context.Context, elected func(ctx context.Context)) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	var (
+		resign  func()
+		renewed time.Time
+	)
+	demote := func(why string) {
+		if resign == nil {
+			return
+		}
+		l.token.Store(0)
+		resign()
+		resign = nil
+		log.Printf("leadership lost (%s), standing by", why)
+	}
+	defer func() {
+		demote("shutting down")
+		l.store.ReleaseLease(context.Background(), leaseName, l.holder)
+	}()
+
+	for {
+		token, err := l.store.AcquireLease(ctx, leaseName, l.holder, l.ttl)
+		switch {
+		case err != nil:
+			log.Printf("lease renewal failed: %v", err)
+			if time.Since(renewed) >= l.ttl {
+				demote("lease expired")
+			}
+		case token == 0:
+			demote("lease held by another instance")
+		default:
+			renewed = time.Now()
+			if token != l.token.Load() {
+				demote("lease re-acquired")
+				resign = l.lead(ctx, token, elected)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
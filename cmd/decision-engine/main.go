@@ -2,16 +2,23 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 
+	"github.com/vineet4007/real-time-canary-control-plane/internal/bolt"
 	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
 	grpcsrv "github.com/vineet4007/real-time-canary-control-plane/internal/grpc"
 	"github.com/vineet4007/real-time-canary-control-plane/internal/redis"
-	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
 )
 
 const (
@@ -19,150 +26,170 @@ const (
 	telemetryTopic = "telemetry.raw"
 	decisionTopic  = "rollout.decisions"
 	consumerGroup  = "decision-engine"
-	serviceID      = "checkout-service"
+	policyDir      = "deploy/policies"
+	historyActor   = "decision-engine"
 )
 
 func main() {
+	var (
+		storeKind       = flag.String("store", "redis", "state backend: redis, bolt or memory")
+		boltPath        = flag.String("bolt-path", "canary.db", "database file (-store=bolt)")
+		historyMaxAge   = flag.Duration("history-retention", 30*24*time.Hour, "how long rollout history is kept (0 = forever)")
+		historyMaxLen   = flag.Int64("history-max-entries", 10000, "cap on history entries per service (0 = unlimited)")
+		instanceID      = flag.String("instance-id", defaultInstanceID(), "identity used in leader election")
+		leaseTTL        = flag.Duration("lease-ttl", 15*time.Second, "leader lease duration; bounds failover time")
+		checkpointEvery = flag.Duration("checkpoint-interval", 5*time.Second, "how often open windows and Kafka offsets are checkpointed")
+		outboxEvery     = flag.Duration("outbox-retry-interval", time.Second, "how often undelivered decisions are retried")
+	)
+	redisConfig := redisFlags()
+	flag.Parse()
+
 	log.Println("starting decision engine")
 
-	// 1️⃣ Load rollout policy (Policy-as-Code)
-	policy, err := decision.LoadPolicy("deploy/policies/checkout.yaml")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 1️⃣ State store (state + idempotency + policies + history)
+	store, closeStore, err := openStore(*storeKind, redisConfig(), *boltPath)
 	if err != nil {
-		log.Fatalf("failed to load policy: %v", err)
+		log.Fatalf("failed to open %s store: %v", *storeKind, err)
 	}
+	defer closeStore()
+	store.SetHistoryRetention(storage.HistoryRetention{
+		MaxAge: *historyMaxAge,
+		MaxLen: *historyMaxLen,
+	})
 
-	engine := decision.NewEngine(policy)
-
-	// 2️⃣ Redis store (state + idempotency)
-	store := redis.New("localhost:6379")
+	// 2️⃣ Seed default policies (Policy-as-Code); Redis copies win
+	if err := seedPolicies(ctx, store, policyDir); err != nil {
+		log.Fatalf("failed to seed policies: %v", err)
+	}
 
-	// 3️⃣ gRPC control plane
-	grpcServer := grpcsrv.NewServer()
+	// 3️⃣ Rollout state machine + gRPC control plane
+	machine := rollout.NewMachine(logTransition)
+	grpcServer := grpcsrv.NewServer(store, machine)
+	if err := grpcServer.Fanout(ctx); err != nil {
+		log.Fatalf("failed to subscribe to decisions: %v", err)
+	}
 	go grpcsrv.Run(grpcServer)
 
-	// 4️⃣ Kafka reader (telemetry)
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers: []string{broker},
-		Topic:   telemetryTopic,
-		GroupID: consumerGroup,
-	})
-	defer reader.Close()
-
-	// 5️⃣ Kafka writer (decisions)
+	// 4️⃣ Kafka writer (decisions), fed by the outbox relay
 	writer := kafka.NewWriter(kafka.WriterConfig{
-		Brokers: []string{broker},
-		Topic:   decisionTopic,
+		Brokers:  []string{broker},
+		Topic:    decisionTopic,
+		Balancer: &kafka.Hash{},
 	})
 	defer writer.Close()
 
-	eventsCh := make(chan decision.Telemetry, 256)
+	// 5️⃣ One evaluation loop per stored policy, kept in sync via pub/sub
+	lead := newLeader(store, *instanceID, *leaseTTL)
+	relay := newRelay(store, writer, grpcServer, *outboxEvery)
+	registry := newRegistry(&deps{
+		store:   store,
+		machine: machine,
+		relay:   relay,
+		lead:    lead,
+	})
 
-	// 6️⃣ Non-blocking Kafka consumer
-	go func() {
-		for {
-			msg, err := reader.ReadMessage(context.Background())
-			if err != nil {
-				log.Printf("kafka read error: %v", err)
-				continue
-			}
+	updates, err := store.SubscribePolicies(ctx)
+	if err != nil {
+		log.Fatalf("failed to subscribe to policy updates: %v", err)
+	}
 
-			var te rolloutpb.TelemetryEvent
-			if err := proto.Unmarshal(msg.Value, &te); err != nil {
-				log.Printf("invalid telemetry payload")
+	go func() {
+		for key := range updates {
+			if key == storage.ResyncPolicies {
+				if err := registry.syncAll(ctx); err != nil {
+					log.Printf("failed to resync policies: %v", err)
+				}
 				continue
 			}
-
-			eventsCh <- decision.Telemetry{
-				ServiceID: te.ServiceId,
-				LatencyMs: te.LatencyMs,
-				IsError:   te.Error,
-				Timestamp: te.TimestampUnixMs,
-			}
+			registry.refresh(ctx, key)
 		}
+		log.Printf("policy update subscription closed")
 	}()
 
-	window := make([]decision.Telemetry, 0)
-	ticker := time.NewTicker(time.Duration(policy.WindowSeconds) * time.Second)
-	defer ticker.Stop()
-
-	// 7️⃣ Main control loop
-	for {
-		select {
-		case ev := <-eventsCh:
-			window = append(window, ev)
-
-		case <-ticker.C:
-			evaluateWindow(engine, store, writer, grpcServer, window)
-			window = nil
+	// 6️⃣ Only the leader evaluates; standbys campaign until it goes away
+	lead.run(ctx, func(ctx context.Context) {
+		if err := registry.activate(ctx); err != nil {
+			log.Printf("failed to load policies: %v", err)
 		}
-	}
+		relayed := make(chan struct{})
+		go func() {
+			defer close(relayed)
+			relay.run(ctx)
+		}()
+		newConsumer(registry, store, lead, *checkpointEvery).run(ctx)
+		registry.deactivate()
+		<-relayed
+	})
 }
 
-func evaluateWindow(
-	engine *decision.Engine,
-	store *redis.Store,
-	writer *kafka.Writer,
-	grpcServer *grpcsrv.Server,
-	events []decision.Telemetry,
-) {
-	result := engine.Evaluate(events)
-	windowID := time.Now().Truncate(30 * time.Second).String()
-
-	ok, err := store.IdempotentDecision(context.Background(), serviceID, windowID)
-	if err != nil || !ok {
-		log.Printf("duplicate decision skipped")
-		return
-	}
-
-	state := &redis.State{
-		ServiceID:    serviceID,
-		Version:      "v1",
-		LastDecision: string(result),
-		State:        mapRolloutState(result),
-	}
+func defaultInstanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
 
-	if err := store.Save(context.Background(), state); err != nil {
-		log.Printf("failed to persist state: %v", err)
-		return
+// openStore opens the configured backend. memory keeps nothing across
+// restarts and is only useful for trying the engine out.
+func openStore(kind string, redisConfig redis.Config, boltPath string) (storage.Store, func(), error) {
+	switch kind {
+	case "redis":
+		s, err := redis.NewFromConfig(redisConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Migrate before seeding, or the seeded policies would shadow
+		// the ones stored under legacy keys.
+		moved, err := s.MigrateLegacyKeys(context.Background())
+		if err != nil {
+			s.Close()
+			return nil, nil, fmt.Errorf("migrate legacy keys: %w", err)
+		}
+		if moved > 0 {
+			log.Printf("migrated %d legacy keys to the default tenant", moved)
+		}
+		return s, func() { s.Close() }, nil
+	case "bolt":
+		s, err := bolt.Open(boltPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, func() { s.Close() }, nil
+	case "memory":
+		return storage.NewMemory(), func() {}, nil
 	}
+	return nil, nil, fmt.Errorf("unknown store %q", kind)
+}
 
-	event := &rolloutpb.DecisionEvent{
-		ServiceId:       serviceID,
-		Decision:        mapDecision(result),
-		Reason:          "policy-based window evaluation",
-		TimestampUnixMs: time.Now().UnixMilli(),
+// seedPolicies stores the YAML policies in dir as version 1 of each
+// service's policy, leaving any policy already in Redis untouched.
+func seedPolicies(ctx context.Context, store storage.Store, dir string) error {
+	policies, err := decision.LoadPolicies(dir)
+	if err != nil {
+		return err
 	}
 
-	bytes, _ := proto.Marshal(event)
-
-	writer.WriteMessages(context.Background(), kafka.Message{
-		Key:   []byte(serviceID),
-		Value: bytes,
-	})
-
-	grpcServer.Publish(event)
-
-	log.Printf("decision=%s events=%d", result, len(events))
-}
+	for key, policy := range policies {
+		spec, err := policy.Marshal()
+		if err != nil {
+			return err
+		}
 
-func mapDecision(d decision.DecisionType) rolloutpb.DecisionType {
-	switch d {
-	case decision.Rollback:
-		return rolloutpb.DecisionType_ROLLBACK
-	case decision.Pause:
-		return rolloutpb.DecisionType_PAUSE
-	default:
-		return rolloutpb.DecisionType_PROMOTE
+		_, err = store.PutPolicy(ctx, key, string(spec), 0)
+		if errors.Is(err, storage.ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("seeded policy service=%s from %s", key, dir)
 	}
+	return nil
 }
 
-func mapRolloutState(d decision.DecisionType) redis.RolloutState {
-	switch d {
-	case decision.Rollback:
-		return redis.RolledBack
-	case decision.Pause:
-		return redis.Paused
-	default:
-		return redis.Promoted
+func logTransition(st *storage.State, from, to storage.RolloutState) {
+	if from != to {
+		log.Printf("service=%s transition %s -> %s", st.Key(), from, to)
 	}
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/redis"
+)
+
+// redisFlags registers the Redis connection flags and returns a func
+// that builds the config once flags are parsed. Passwords default to
+// REDIS_PASSWORD and REDIS_SENTINEL_PASSWORD so they stay out of ps.
+func redisFlags() func() redis.Config {
+	var (
+		addrs        = flag.String("redis-addr", "localhost:6379", "Redis node, Sentinel or Cluster seed addresses, comma separated")
+		master       = flag.String("redis-master", "", "Sentinel master name; -redis-addr then lists Sentinels")
+		cluster      = flag.Bool("redis-cluster", false, "use Redis Cluster")
+		username     = flag.String("redis-username", "", "ACL username")
+		password     = flag.String("redis-password", os.Getenv("REDIS_PASSWORD"), "password (default $REDIS_PASSWORD)")
+		sentUser     = flag.String("redis-sentinel-username", "", "Sentinel ACL username")
+		sentPassword = flag.String("redis-sentinel-password", os.Getenv("REDIS_SENTINEL_PASSWORD"), "Sentinel password (default $REDIS_SENTINEL_PASSWORD)")
+		db           = flag.Int("redis-db", 0, "database number (not with -redis-cluster)")
+		useTLS       = flag.Bool("redis-tls", false, "connect over TLS")
+		caFile       = flag.String("redis-tls-ca", "", "CA bundle verifying the server (default: system roots)")
+		certFile     = flag.String("redis-tls-cert", "", "client certificate")
+		keyFile      = flag.String("redis-tls-key", "", "client certificate key")
+		serverName   = flag.String("redis-tls-server-name", "", "expected server name, if not the dialled host")
+		poolSize     = flag.Int("redis-pool-size", 0, "connections per node (0 = 10 per CPU)")
+		minIdle      = flag.Int("redis-min-idle", 0, "idle connections kept open per node")
+		dialTimeout  = flag.Duration("redis-dial-timeout", 5*time.Second, "connect timeout")
+		readTimeout  = flag.Duration("redis-read-timeout", 3*time.Second, "read timeout")
+		writeTimeout = flag.Duration("redis-write-timeout", 3*time.Second, "write timeout")
+	)
+
+	return func() redis.Config {
+		cfg := redis.Config{
+			Addrs:            strings.Split(*addrs, ","),
+			MasterName:       *master,
+			Cluster:          *cluster,
+			Username:         *username,
+			Password:         *password,
+			SentinelUsername: *sentUser,
+			SentinelPassword: *sentPassword,
+			DB:               *db,
+			PoolSize:         *poolSize,
+			MinIdleConns:     *minIdle,
+			DialTimeout:      *dialTimeout,
+			ReadTimeout:      *readTimeout,
+			WriteTimeout:     *writeTimeout,
+		}
+		if *useTLS || *caFile != "" || *certFile != "" {
+			cfg.TLS = &redis.TLSConfig{
+				CAFile:     *caFile,
+				CertFile:   *certFile,
+				KeyFile:    *keyFile,
+				ServerName: *serverName,
+			}
+		}
+		return cfg
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// deps are shared by every service loop.
+type deps struct {
+	store   storage.Store
+	machine *rollout.Machine
+	relay   *relay
+	lead    *leader
+}
+
+// registry tracks the running service loops and keeps them in sync with
+// the stored policies.
+type registry struct {
+	*deps
+
+	mu sync.RWMutex
+	// loops are keyed by storage.Key.
+	loops map[string]*serviceLoop
+	// active is true while this instance leads; standbys run no loops.
+	active bool
+}
+
+func newRegistry(d *deps) *registry {
+	return &registry{
+		deps:  d,
+		loops: make(map[string]*serviceLoop),
+	}
+}
+
+func (r *registry) get(key string) (*serviceLoop, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	loop, ok := r.loops[key]
+	return loop, ok
+}
+
+// activate starts a loop for every stored policy and keeps following
+// policy changes until deactivate.
+func (r *registry) activate(ctx context.Context) error {
+	r.mu.Lock()
+	r.active = true
+	r.mu.Unlock()
+
+	return r.syncAll(ctx)
+}
+
+// deactivate stops every loop.
+func (r *registry) deactivate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active = false
+	for key, loop := range r.loops {
+		loop.stop()
+		delete(r.loops, key)
+	}
+}
+
+// syncAll starts or updates a loop for every stored policy and stops
+// the loops whose policy is gone.
+func (r *registry) syncAll(ctx context.Context) error {
+	recs, err := r.store.ListPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]bool, len(recs))
+	for _, rec := range recs {
+		stored[rec.Key()] = true
+		r.apply(rec)
+	}
+
+	r.mu.RLock()
+	var gone []string
+	for key := range r.loops {
+		if !stored[key] {
+			gone = append(gone, key)
+		}
+	}
+	r.mu.RUnlock()
+	for _, key := range gone {
+		r.remove(key)
+	}
+	return nil
+}
+
+// refresh re-reads one service's policy after a change notification.
+func (r *registry) refresh(ctx context.Context, key string) {
+	rec, err := r.store.GetPolicy(ctx, key)
+	if err != nil {
+		log.Printf("service=%s failed to reload policy: %v", key, err)
+		return
+	}
+
+	if rec == nil {
+		r.remove(key)
+		return
+	}
+	r.apply(rec)
+}
+
+func (r *registry) apply(rec *storage.PolicyRecord) {
+	policy, err := decision.ParsePolicy([]byte(rec.Spec))
+	if err == nil {
+		err = policy.Validate()
+	}
+	if err == nil && policy.Key() != rec.Key() {
+		err = fmt.Errorf("policy is for %s", policy.Key())
+	}
+	if err != nil {
+		log.Printf("service=%s ignoring invalid policy version=%d: %v", rec.Key(), rec.Version, err)
+		return
+	}
+	policy.Version = rec.Version
+	key := rec.Key()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active {
+		return
+	}
+	if loop, ok := r.loops[key]; ok {
+		// rec was just read from the store, so it is the current policy
+		// even at a lower version: versions restart after a delete.
+		if loop.policyVersion() != policy.Version {
+			loop.update(policy)
+			log.Printf("service=%s policy updated version=%d", key, policy.Version)
+		}
+		return
+	}
+
+	loop := newServiceLoop(policy, r.deps)
+	r.loops[key] = loop
+	go loop.run()
+	log.Printf("evaluating service=%s window=%ds policy_version=%d",
+		key, policy.WindowSeconds, policy.Version)
+}
+
+// windows returns the open window of every running loop.
+func (r *registry) windows() map[string][]decision.Telemetry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string][]decision.Telemetry, len(r.loops))
+	for key, loop := range r.loops {
+		if events := loop.window(); len(events) > 0 {
+			out[key] = events
+		}
+	}
+	return out
+}
+
+func (r *registry) remove(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if loop, ok := r.loops[key]; ok {
+		loop.stop()
+		delete(r.loops, key)
+		log.Printf("service=%s policy deleted, evaluation stopped", key)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+func TestApplyReplacesAPolicyRecreatedAtALowerVersion(t *testing.T) {
+	loop, _ := newTestLoop(t, sprtTestPolicy())
+	r := newRegistry(loop.deps)
+	r.active = true
+	t.Cleanup(r.deactivate)
+
+	record := func(version int64, windowSeconds int) *storage.PolicyRecord {
+		return &storage.PolicyRecord{
+			Tenant:    storage.DefaultTenant,
+			ServiceID: "checkout-service",
+			Version:   version,
+			Spec: fmt.Sprintf(`
+service: checkout-service
+window_seconds: %d
+thresholds:
+  error_rate: 0.05
+  latency_ms: 500
+actions:
+  on_error: ROLLBACK
+  on_latency: PAUSE
+  on_success: PROMOTE
+`, windowSeconds),
+		}
+	}
+
+	r.apply(record(3, 30))
+	// The loop missed the delete; the policy was then put again and
+	// started over at version 1.
+	r.apply(record(1, 60))
+
+	got, ok := r.get(loop.key)
+	if !ok {
+		t.Fatal("expected a loop for checkout-service")
+	}
+	if v := got.policyVersion(); v != 1 {
+		t.Fatalf("expected the recreated policy at version 1, got version %d", v)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+
+	grpcsrv "github.com/vineet4007/real-time-canary-control-plane/internal/grpc"
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// outboxBatch is how many messages of one service are read per pass.
+const outboxBatch = 100
+
+// relay delivers the decisions saved in the outbox to Kafka and the gRPC
+// streams, and acknowledges each only once Kafka has accepted it. A
+// failed delivery is retried on the next pass, so a decision may be
+// delivered more than once but is never lost; its decision ID is kept
+// across retries for consumers to deduplicate.
+type relay struct {
+	store      storage.OutboxStore
+	writer     *kafka.Writer
+	grpcServer *grpcsrv.Server
+	interval   time.Duration
+	wake       chan struct{}
+}
+
+func newRelay(store storage.OutboxStore, writer *kafka.Writer, grpcServer *grpcsrv.Server, interval time.Duration) *relay {
+	return &relay{
+		store:      store,
+		writer:     writer,
+		grpcServer: grpcServer,
+		interval:   interval,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// kick asks for a pass now rather than at the next interval.
+func (r *relay) kick() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers until ctx is cancelled.
+func (r *relay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain delivers every pending message. A service's messages go out in
+// order, so its first failure leaves the rest for the next pass.
+func (r *relay) drain(ctx context.Context) {
+	keys, err := r.store.OutboxKeys(ctx)
+	if err != nil {
+		log.Printf("failed to list outboxes: %v", err)
+		return
+	}
+
+	for _, key := range keys {
+		for ctx.Err() == nil {
+			msgs, err := r.store.PendingOutbox(ctx, key, outboxBatch)
+			if err != nil {
+				log.Printf("service=%s failed to read outbox: %v", key, err)
+				break
+			}
+			if err := r.deliver(ctx, key, msgs); err != nil {
+				log.Printf("service=%s decision delivery failed, retrying in %s: %v", key, r.interval, err)
+				break
+			}
+			if len(msgs) < outboxBatch {
+				break
+			}
+		}
+	}
+}
+
+func (r *relay) deliver(ctx context.Context, key string, msgs []*storage.OutboxMessage) error {
+	for _, msg := range msgs {
+		err := r.writer.WriteMessages(ctx, kafka.Message{
+			Key:     []byte(key),
+			Value:   msg.Payload,
+			Headers: []kafka.Header{{Key: "decision-id", Value: []byte(msg.DecisionID)}},
+		})
+		if err != nil {
+			return err
+		}
+
+		var event rolloutpb.DecisionEvent
+		if err := proto.Unmarshal(msg.Payload, &event); err != nil {
+			log.Printf("service=%s outbox message %s is not a decision: %v", key, msg.DecisionID, err)
+		} else {
+			r.grpcServer.Publish(&event)
+		}
+
+		if err := r.store.AckOutbox(ctx, key, msg.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// serviceLoop owns the evaluation window of a single service.
+type serviceLoop struct {
+	// key is the service's storage.Key; it also names the service in logs.
+	key     string
+	policy  *decision.Policy
+	engine  *decision.Engine
+	sprt    *decision.SPRT
+	events  chan loopInput
+	updates chan *decision.Policy
+	done    chan struct{}
+	version atomic.Int64
+	// idle is set while the service has no rollout in progress or a
+	// manual pause holds it, so the skip is only logged once.
+	idle bool
+
+	*deps
+}
+
+func newServiceLoop(policy *decision.Policy, d *deps) *serviceLoop {
+	l := &serviceLoop{
+		key:     policy.Key(),
+		policy:  policy,
+		engine:  decision.NewEngine(policy),
+		sprt:    newSPRT(policy),
+		events:  make(chan loopInput, 256),
+		updates: make(chan *decision.Policy, 1),
+		done:    make(chan struct{}),
+		deps:    d,
+	}
+	l.version.Store(policy.Version)
+	return l
+}
+
+// newSPRT returns the continuous evidence accumulator for policies in
+// sprt mode, nil otherwise.
+func newSPRT(policy *decision.Policy) *decision.SPRT {
+	if policy.Analysis.Mode != decision.ModeSPRT {
+		return nil
+	}
+	return decision.NewSPRT(policy.Analysis.SPRT)
+}
+
+func (l *serviceLoop) policyVersion() int64 {
+	return l.version.Load()
+}
+
+// update hands a newer policy to the loop; it takes effect on the next
+// event or tick.
+func (l *serviceLoop) update(policy *decision.Policy) {
+	l.version.Store(policy.Version)
+	select {
+	case <-l.updates:
+	default:
+	}
+	l.updates <- policy
+}
+
+// loopInput is an event, or a request for a copy of the open window.
+// Both travel on one channel so a snapshot includes every event sent
+// before it.
+type loopInput struct {
+	ev       decision.Telemetry
+	snapshot chan<- []decision.Telemetry
+}
+
+// send delivers an event unless the loop has been stopped.
+func (l *serviceLoop) send(ev decision.Telemetry) {
+	select {
+	case l.events <- loopInput{ev: ev}:
+	case <-l.done:
+	}
+}
+
+// window returns the events of the open window, or nil once stopped.
+func (l *serviceLoop) window() []decision.Telemetry {
+	reply := make(chan []decision.Telemetry, 1)
+	select {
+	case l.events <- loopInput{snapshot: reply}:
+	case <-l.done:
+		return nil
+	}
+	select {
+	case events := <-reply:
+		return events
+	case <-l.done:
+		return nil
+	}
+}
+
+func (l *serviceLoop) stop() {
+	close(l.done)
+}
+
+func (l *serviceLoop) run() {
+	window := make([]decision.Telemetry, 0)
+	ticker := time.NewTicker(l.windowLength())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case in := <-l.events:
+			if in.snapshot != nil {
+				in.snapshot <- append([]decision.Telemetry(nil), window...)
+				continue
+			}
+			ev := in.ev
+			window = append(window, ev)
+
+			// In sprt mode act as soon as the evidence is conclusive
+			// instead of waiting for the tick.
+			if l.sprt == nil {
+				continue
+			}
+			if _, done := l.sprt.Observe(ev); done {
+				if l.evaluateWindow(window) {
+					window = nil
+					l.sprt.Reset()
+					ticker.Reset(l.windowLength())
+				}
+			}
+
+		case policy := <-l.updates:
+			if policy.WindowSeconds != l.policy.WindowSeconds {
+				ticker.Reset(time.Duration(policy.WindowSeconds) * time.Second)
+			}
+			l.policy = policy
+			l.engine = decision.NewEngine(policy)
+			if l.sprt = newSPRT(policy); l.sprt != nil {
+				for _, ev := range window {
+					l.sprt.Observe(ev)
+				}
+			}
+
+		case <-l.done:
+			return
+
+		case <-ticker.C:
+			if l.evaluateWindow(window) {
+				window = nil
+				if l.sprt != nil {
+					l.sprt.Reset()
+				}
+			}
+		}
+	}
+}
+
+func (l *serviceLoop) windowLength() time.Duration {
+	return time.Duration(l.policy.WindowSeconds) * time.Second
+}
+
+// windowClaimTTL is how many window lengths a window's decision stays
+// recorded, covering replays after a restart or failover.
+const windowClaimTTL = 10
+
+// since drops the events from before ts (unix ms).
+func since(events []decision.Telemetry, ts int64) []decision.Telemetry {
+	kept := events[:0:0]
+	for _, ev := range events {
+		if ev.Timestamp >= ts {
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
+
+// newest returns the latest event time (unix ms) in events.
+func newest(events []decision.Telemetry) int64 {
+	var ts int64
+	for _, ev := range events {
+		ts = max(ts, ev.Timestamp)
+	}
+	return ts
+}
+
+// evaluateWindow judges the window and publishes the decision. It
+// reports false when a sequential test is still pending, in which case
+// the caller keeps the events for the next evaluation.
+func (l *serviceLoop) evaluateWindow(events []decision.Telemetry) bool {
+	ctx, leading := l.lead.fence(context.Background())
+	if !leading {
+		return true
+	}
+
+	prev, err := l.store.Get(ctx, l.key)
+	if err != nil {
+		log.Printf("service=%s failed to load state: %v", l.key, err)
+		return true
+	}
+
+	// Only a rollout started through StartRollout is evaluated, and only
+	// on telemetry from after it started.
+	if prev == nil || rollout.IsTerminal(prev.State) {
+		if !l.idle {
+			if prev == nil {
+				log.Printf("service=%s has no rollout, waiting for StartRollout", l.key)
+			} else {
+				log.Printf("service=%s rollout is %s, evaluation stopped", l.key, prev.State)
+			}
+			l.idle = true
+		}
+		return true
+	}
+	// A manual pause takes precedence over the windows until it expires
+	// or an operator clears it.
+	if rollout.Held(prev, time.Now()) {
+		if !l.idle {
+			log.Printf("service=%s held by manual pause from %s, evaluation stopped", l.key, prev.Override.Actor)
+			l.idle = true
+		}
+		return true
+	}
+	l.idle = false
+	// Events replayed from a checkpoint saved before the last decision
+	// were part of the batch it judged.
+	if len(events) > 0 {
+		events = since(events, max(prev.StartedAt, prev.DecidedThrough+1))
+		if len(events) == 0 {
+			log.Printf("service=%s window events already decided, dropped", l.key)
+			return true
+		}
+	}
+
+	verdict := l.engine.Evaluate(events)
+	if verdict.Pending {
+		log.Printf("service=%s events=%d %s", l.key, len(events), verdict.Reason)
+		return false
+	}
+
+	// The first decision recorded for a batch is the answer for that
+	// batch; a replay of it reuses that answer. A rollout that supersedes
+	// another gets its own answers.
+	rolloutID := prev.RolloutID
+	windowID := l.policy.BatchID(events)
+	if windowID == "" {
+		// A tick without telemetry has nothing to replay; it is named
+		// after the state it judged so it is applied at most once.
+		windowID = fmt.Sprintf("%s/%ds/empty@%d", l.policy.Service, l.policy.WindowSeconds, prev.Revision)
+	}
+	won, claimed, err := l.store.ClaimWindow(ctx, l.key, rolloutID+"/"+windowID, &storage.WindowDecision{
+		Decision:  string(verdict.Decision),
+		Reason:    verdict.Reason,
+		DecidedAt: time.Now().UnixMilli(),
+	}, windowClaimTTL*l.windowLength())
+	if err != nil {
+		log.Printf("service=%s failed to claim window %s: %v", l.key, windowID, err)
+		return true
+	}
+	if !claimed {
+		log.Printf("service=%s window %s already decided %s, replaying", l.key, windowID, won.Decision)
+		verdict.Decision = decision.DecisionType(won.Decision)
+		verdict.Reason = won.Reason
+	}
+
+	// Another writer (an approval, an operator) may have moved the rollout
+	// since prev was read; Update re-runs the transition on fresh state.
+	metrics := verdictMetrics(verdict)
+	var (
+		result decision.DecisionType
+		reason string
+		from   storage.RolloutState
+	)
+	state, err := storage.Update(ctx, l.store, l.key, func(cur *storage.State) (*storage.State, error) {
+		if cur == nil || rollout.IsTerminal(cur.State) {
+			return nil, errRolloutFinished
+		}
+		if cur.RolloutID != rolloutID {
+			return nil, errRolloutReplaced
+		}
+		if cur.Window == windowID {
+			return nil, errWindowApplied
+		}
+		if rollout.Held(cur, time.Now()) {
+			return nil, errRolloutHeld
+		}
+		from = cur.State
+		next, res, why, err := l.machine.Advance(cur, l.policy, verdict)
+		if err != nil {
+			return nil, err
+		}
+		// A pause that got this far has expired.
+		next.Override = nil
+		next.Window = windowID
+		next.DecidedThrough = max(next.DecidedThrough, newest(events))
+		next.LastVerdict = &storage.VerdictRecord{
+			Verdict:   string(verdict.Decision),
+			Decision:  string(res),
+			Reason:    why,
+			Metrics:   metrics,
+			DecidedAt: time.Now().UnixMilli(),
+		}
+		result, reason = res, why
+		next.Sequence++
+		next.Outbox = []storage.OutboxMessage{l.decisionMessage(next, windowID, result, reason)}
+		next.History = []storage.HistoryEntry{l.historyEntry(next, from, verdict, result, reason)}
+		return next, nil
+	})
+	switch {
+	case errors.Is(err, errRolloutFinished):
+		log.Printf("service=%s rollout finished concurrently, decision=%s dropped", l.key, verdict.Decision)
+		return true
+	case errors.Is(err, errRolloutReplaced):
+		log.Printf("service=%s rollout replaced concurrently, decision=%s dropped", l.key, verdict.Decision)
+		return true
+	case errors.Is(err, errRolloutHeld):
+		log.Printf("service=%s paused manually, decision=%s dropped", l.key, verdict.Decision)
+		return true
+	case errors.Is(err, errWindowApplied):
+		log.Printf("service=%s window %s already applied", l.key, windowID)
+		return true
+	case errors.As(err, new(*rollout.TransitionError)):
+		log.Printf("service=%s decision=%s rejected: %v", l.key, verdict.Decision, err)
+		return true
+	case errors.Is(err, storage.ErrFenced):
+		log.Printf("service=%s decision=%s dropped, no longer leader", l.key, verdict.Decision)
+		return true
+	case err != nil:
+		log.Printf("service=%s failed to persist state: %v", l.key, err)
+		return true
+	}
+
+	l.relay.kick()
+
+	log.Printf("service=%s decision=%s state=%s weight=%d events=%d canary_rps=%.2f stable_rps=%.2f reason=%q",
+		l.key, result, state.State, state.TrafficWeight, len(events),
+		verdict.Metrics.CanaryRPS, verdict.Metrics.StableRPS, reason)
+	return true
+}
+
+// verdictMetrics flattens the metrics behind a verdict for the rollout
+// state and its history.
+func verdictMetrics(verdict decision.Verdict) map[string]float64 {
+	m := verdict.Metrics
+	metrics := map[string]float64{
+		"canary_events":  float64(m.CanaryEvents),
+		"stable_events":  float64(m.StableEvents),
+		"error_rate":     m.ErrorRate,
+		"avg_latency_ms": m.AvgLatencyMs,
+		"canary_rps":     m.CanaryRPS,
+		"stable_rps":     m.StableRPS,
+	}
+	if verdict.Probability > 0 {
+		metrics["probability"] = verdict.Probability
+	}
+	for name, r := range m.Ratios {
+		metrics["ratio."+name] = r.Canary
+	}
+	return metrics
+}
+
+// decisionMessage is the outbox message announcing the decision that
+// moved the rollout to st. A window is decided at most once per rollout,
+// so the two name the decision.
+func (l *serviceLoop) decisionMessage(
+	st *storage.State,
+	windowID string,
+	result decision.DecisionType,
+	reason string,
+) storage.OutboxMessage {
+	id := decisionID(st.RolloutID, windowID)
+	bytes, _ := proto.Marshal(&rolloutpb.DecisionEvent{
+		DecisionId:      id,
+		RolloutId:       st.RolloutID,
+		Tenant:          l.policy.Tenant,
+		ServiceId:       l.policy.Service,
+		Decision:        mapDecision(result),
+		Source:          rolloutpb.DecisionSource_AUTOMATED,
+		Reason:          reason,
+		TimestampUnixMs: time.Now().UnixMilli(),
+		TrafficWeight:   int32(st.TrafficWeight),
+		Sequence:        st.Sequence,
+	})
+	return storage.OutboxMessage{DecisionID: id, Payload: bytes}
+}
+
+// decisionID names a window's decision the same way on every delivery.
+func decisionID(rolloutID, windowID string) string {
+	return rolloutID + "/" + windowID
+}
+
+// historyEntry is the audit log entry for the decision that moved the
+// rollout to state, with the verdict behind it (state's LastVerdict).
+func (l *serviceLoop) historyEntry(
+	state *storage.State,
+	from storage.RolloutState,
+	verdict decision.Verdict,
+	result decision.DecisionType,
+	reason string,
+) storage.HistoryEntry {
+	return storage.HistoryEntry{
+		RolloutID:     state.RolloutID,
+		Tenant:        l.policy.Tenant,
+		ServiceID:     l.policy.Service,
+		Kind:          storage.HistoryDecision,
+		Actor:         historyActor,
+		From:          from,
+		To:            state.State,
+		Verdict:       string(verdict.Decision),
+		Decision:      string(result),
+		Reason:        reason,
+		PolicyVersion: l.policy.Version,
+		TrafficWeight: state.TrafficWeight,
+		Metrics:       state.LastVerdict.Metrics,
+		DecisionID:    decisionID(state.RolloutID, state.Window),
+		Sequence:      state.Sequence,
+	}
+}
+
+var (
+	errRolloutFinished = errors.New("rollout finished")
+	errRolloutReplaced = errors.New("rollout replaced")
+	errWindowApplied   = errors.New("window already applied")
+	errRolloutHeld     = errors.New("rollout held by manual pause")
+)
+
+func mapDecision(d decision.DecisionType) rolloutpb.DecisionType {
+	switch d {
+	case decision.Rollback:
+		return rolloutpb.DecisionType_ROLLBACK
+	case decision.Pause:
+		return rolloutpb.DecisionType_PAUSE
+	default:
+		return rolloutpb.DecisionType_PROMOTE
+	}
+}
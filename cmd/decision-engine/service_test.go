@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const testStart = 1_718_000_010_000
+
+// newTestLoop returns a loop that leads over a memory store holding a
+// rollout of policy started at testStart.
+func newTestLoop(t *testing.T, policy *decision.Policy) (*serviceLoop, storage.Store) {
+	t.Helper()
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	token, err := store.AcquireLease(ctx, leaseName, "test", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lead := newLeader(store, "test", time.Minute)
+	lead.token.Store(token)
+
+	machine := rollout.NewMachine()
+	st, err := machine.Start(nil, policy, rollout.StartRequest{Version: "v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.StartedAt = testStart
+	if err := store.Save(storage.WithFence(ctx, leaseName, token), st); err != nil {
+		t.Fatal(err)
+	}
+
+	return newServiceLoop(policy, &deps{
+		store:   store,
+		machine: machine,
+		relay:   newRelay(store, nil, nil, time.Second),
+		lead:    lead,
+	}), store
+}
+
+func sprtTestPolicy() *decision.Policy {
+	p := &decision.Policy{Service: "checkout-service", WindowSeconds: 30}
+	p.Thresholds.ErrorRate = 0.05
+	p.Thresholds.LatencyMs = 500
+	p.Actions.OnError = decision.Rollback
+	p.Actions.OnLatency = decision.Pause
+	p.Actions.OnSuccess = decision.Promote
+	p.Analysis.Mode = decision.ModeSPRT
+	p.Analysis.SPRT = decision.SPRTConfig{P0: 0.01, P1: 0.05, Alpha: 0.05, Beta: 0.1}
+	p.Steps = []decision.Step{{Weight: 10}, {Weight: 50}, {Weight: 100}}
+	return p
+}
+
+func canaryEvents(n int, start int64, isError bool) []decision.Telemetry {
+	events := make([]decision.Telemetry, n)
+	for i := range events {
+		events[i] = decision.Telemetry{
+			ServiceID: "checkout-service",
+			LatencyMs: 100,
+			IsError:   isError,
+			Timestamp: start + int64(i)*10,
+		}
+	}
+	return events
+}
+
+func TestTwoSPRTDecisionsInOnePolicyWindowAreBothApplied(t *testing.T) {
+	policy := sprtTestPolicy()
+	loop, store := newTestLoop(t, policy)
+	ctx := context.Background()
+
+	healthy := canaryEvents(60, testStart+1_000, false)
+	failing := canaryEvents(3, testStart+5_000, true)
+	if policy.WindowID(healthy[0].Timestamp) != policy.WindowID(failing[2].Timestamp) {
+		t.Fatal("test batches must share a policy window")
+	}
+
+	if !loop.evaluateWindow(healthy) {
+		t.Fatal("expected the healthy batch to be decided")
+	}
+	st, err := store.Get(ctx, loop.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.Canary || st.TrafficWeight != 50 {
+		t.Fatalf("expected an early promote to 50%%, got %s at %d%%", st.State, st.TrafficWeight)
+	}
+
+	// A replay of the same batch is recognised rather than re-decided.
+	if !loop.evaluateWindow(healthy) {
+		t.Fatal("expected the replayed batch to be dropped")
+	}
+	if msgs, _ := store.PendingOutbox(ctx, loop.key, 10); len(msgs) != 1 {
+		t.Fatalf("a replayed batch must not be decided again, got %d decisions", len(msgs))
+	}
+
+	if !loop.evaluateWindow(failing) {
+		t.Fatal("expected the failing batch to be decided")
+	}
+	st, err = store.Get(ctx, loop.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.RolledBack {
+		t.Fatalf("a second conclusive result in the same window must be applied, got %s", st.State)
+	}
+
+	msgs, err := store.PendingOutbox(ctx, loop.key, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || msgs[0].DecisionID == msgs[1].DecisionID {
+		t.Fatalf("expected two decisions with distinct IDs, got %+v", msgs)
+	}
+}
+
+func TestReplayedEventsOfADecidedBatchAreNotJudgedAgain(t *testing.T) {
+	policy := sprtTestPolicy()
+	loop, store := newTestLoop(t, policy)
+	ctx := context.Background()
+
+	healthy := canaryEvents(60, testStart+1_000, false)
+	if !loop.evaluateWindow(healthy) {
+		t.Fatal("expected the healthy batch to be decided")
+	}
+
+	// After a crash the checkpoint restores the decided events and Kafka
+	// redelivers newer ones, so the next batch spans both.
+	failing := canaryEvents(3, testStart+5_000, true)
+	if !loop.evaluateWindow(append(append([]decision.Telemetry(nil), healthy...), failing...)) {
+		t.Fatal("expected the new events to be decided")
+	}
+	st, err := store.Get(ctx, loop.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.RolledBack || st.Step != 1 {
+		t.Fatalf("expected only the failing events to be judged, got %s at step %d", st.State, st.Step)
+	}
+	if st.DecidedThrough != failing[2].Timestamp {
+		t.Fatalf("expected decisions through %d, got %d", failing[2].Timestamp, st.DecidedThrough)
+	}
+}
@@ -10,11 +10,13 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
 )
 
 const (
 	kafkaBroker = "localhost:9092"
 	topic       = "telemetry.raw"
+	tenant      = storage.DefaultTenant
 	serviceID   = "checkout-service"
 )
 
@@ -30,10 +32,16 @@ func main() {
 
 	for {
 		event := &rolloutpb.TelemetryEvent{
-			ServiceId:        serviceID,
-			LatencyMs:        rand.Float64()*400 + 50,
-			Error:            rand.Intn(100) < 5,
-			TimestampUnixMs:  time.Now().UnixMilli(),
+			Tenant:          tenant,
+			ServiceId:       serviceID,
+			LatencyMs:       rand.Float64()*400 + 50,
+			Error:           rand.Intn(100) < 5,
+			TimestampUnixMs: time.Now().UnixMilli(),
+			Track:           "canary",
+			Counters:        map[string]float64{"payment_attempt": 1},
+		}
+		if rand.Intn(100) < 98 {
+			event.Counters["payment_success"] = 1
 		}
 
 		bytes, err := proto.Marshal(event)
@@ -42,7 +50,7 @@ func main() {
 		}
 
 		msg := kafka.Message{
-			Key:   []byte(serviceID),
+			Key:   []byte(storage.Key(tenant, serviceID)),
 			Value: bytes,
 		}
 
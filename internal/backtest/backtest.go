@@ -0,0 +1,115 @@
+// Package backtest replays recorded telemetry through decision.Engine on a
+// simulated clock. It never talks to Redis or the live decisions topic.
+package backtest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+)
+
+type WindowResult struct {
+	Start    time.Time
+	End      time.Time
+	Events   int
+	Decision decision.DecisionType
+	Reason   string
+	// Pending windows had an inconclusive sequential test; their events
+	// carry over into the next window, as in the live engine.
+	Pending bool
+}
+
+type Summary struct {
+	Windows       int
+	Counts        map[decision.DecisionType]int
+	FirstRollback *time.Time
+}
+
+// Replay buckets the policy's service events into windows aligned to
+// multiples of the policy window and evaluates each one, including the
+// empty windows a live engine would have ticked through.
+func Replay(policy *decision.Policy, events []decision.Telemetry) []WindowResult {
+	own := make([]decision.Telemetry, 0, len(events))
+	for _, ev := range events {
+		if ev.ServiceID == policy.Service {
+			own = append(own, ev)
+		}
+	}
+	if len(own) == 0 {
+		return nil
+	}
+	sort.SliceStable(own, func(i, j int) bool { return own[i].Timestamp < own[j].Timestamp })
+
+	engine := decision.NewEngine(policy)
+	windowMs := int64(policy.WindowSeconds) * 1000
+	start := policy.WindowStart(own[0].Timestamp)
+
+	var results []WindowResult
+	carry := 0
+	for i := 0; i < len(own); start += windowMs {
+		end := start + windowMs
+		j := i
+		for j < len(own) && own[j].Timestamp < end {
+			j++
+		}
+
+		verdict := engine.Evaluate(own[carry:j])
+		results = append(results, WindowResult{
+			Start:    time.UnixMilli(start),
+			End:      time.UnixMilli(end),
+			Events:   j - i,
+			Decision: verdict.Decision,
+			Reason:   verdict.Reason,
+			Pending:  verdict.Pending,
+		})
+		if !verdict.Pending {
+			carry = j
+		}
+		i = j
+	}
+
+	return results
+}
+
+func Summarize(results []WindowResult) Summary {
+	s := Summary{
+		Windows: len(results),
+		Counts:  make(map[decision.DecisionType]int),
+	}
+	for _, r := range results {
+		if r.Pending {
+			continue
+		}
+		s.Counts[r.Decision]++
+		if r.Decision == decision.Rollback && s.FirstRollback == nil {
+			start := r.Start
+			s.FirstRollback = &start
+		}
+	}
+	return s
+}
+
+type Diff struct {
+	Start time.Time
+	A, B  decision.DecisionType
+}
+
+// Compare lists windows where two replays disagree. Only windows that
+// start at the same instant are compared, so policies with different
+// window lengths only line up on common boundaries.
+func Compare(a, b []WindowResult) []Diff {
+	byStart := make(map[int64]decision.DecisionType, len(b))
+	for _, r := range b {
+		byStart[r.Start.UnixMilli()] = r.Decision
+	}
+
+	var diffs []Diff
+	for _, r := range a {
+		other, ok := byStart[r.Start.UnixMilli()]
+		if ok && other != r.Decision {
+			diffs = append(diffs, Diff{Start: r.Start, A: r.Decision, B: other})
+		}
+	}
+	return diffs
+}
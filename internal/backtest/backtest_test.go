@@ -0,0 +1,71 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+)
+
+func testPolicy(errorRate float64) *decision.Policy {
+	p := &decision.Policy{Service: "checkout-service", WindowSeconds: 10}
+	p.Thresholds.ErrorRate = errorRate
+	p.Thresholds.LatencyMs = 500
+	p.Actions.OnError = decision.Rollback
+	p.Actions.OnLatency = decision.Pause
+	p.Actions.OnSuccess = decision.Promote
+	return p
+}
+
+func TestReplayAlignsWindowsToEventTime(t *testing.T) {
+	var events []decision.Telemetry
+	// window [0s,10s): healthy; [10s,20s): empty; [20s,30s): 50% errors
+	for i := 0; i < 10; i++ {
+		events = append(events, decision.Telemetry{ServiceID: "checkout-service", LatencyMs: 100, Timestamp: int64(i * 1000)})
+	}
+	for i := 0; i < 10; i++ {
+		events = append(events, decision.Telemetry{ServiceID: "checkout-service", LatencyMs: 100, IsError: i%2 == 0, Timestamp: int64(20000 + i*1000)})
+	}
+	events = append(events, decision.Telemetry{ServiceID: "search-service", IsError: true, Timestamp: 5000})
+
+	results := Replay(testPolicy(0.05), events)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(results))
+	}
+
+	want := []decision.DecisionType{decision.Promote, decision.Promote, decision.Rollback}
+	for i, r := range results {
+		if r.Decision != want[i] {
+			t.Fatalf("window %d: expected %s, got %s", i, want[i], r.Decision)
+		}
+	}
+	if results[0].Events != 10 || results[1].Events != 0 {
+		t.Fatalf("unexpected event counts: %d, %d", results[0].Events, results[1].Events)
+	}
+
+	lenient := Replay(testPolicy(0.6), events)
+	diffs := Compare(results, lenient)
+	if len(diffs) != 1 || diffs[0].A != decision.Rollback || diffs[0].B != decision.Promote {
+		t.Fatalf("unexpected diffs: %+v", diffs)
+	}
+}
+
+func TestReadFileJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	body := `{"serviceId":"checkout-service","latencyMs":120,"error":true,"timestampUnixMs":"1000"}
+
+{"service_id":"checkout-service","latency_ms":80,"timestamp_unix_ms":2000}
+`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := ReadFile(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || !events[0].IsError || events[1].Timestamp != 2000 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
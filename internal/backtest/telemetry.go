@@ -0,0 +1,21 @@
+package backtest
+
+import (
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+)
+
+// FromProto converts a wire TelemetryEvent into the engine's Telemetry.
+// The decision engine decodes Kafka messages with it too, so a backtest
+// sees exactly what the engine would.
+func FromProto(te *rolloutpb.TelemetryEvent) decision.Telemetry {
+	return decision.Telemetry{
+		ServiceID:  te.ServiceId,
+		LatencyMs:  te.LatencyMs,
+		IsError:    te.Error,
+		Timestamp:  te.TimestampUnixMs,
+		Track:      te.Track,
+		Counters:   te.Counters,
+		Dependency: te.FailingDependency,
+	}
+}
@@ -0,0 +1,132 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// migrateLegacyKeys moves rollouts, policies, history and checkpointed
+// windows stored under a bare service name, as they were before tenants,
+// to the default tenant's key. A legacy record whose new key is already
+// taken fails the migration, and with it Open, naming both keys.
+func migrateLegacyKeys(tx *bbolt.Tx) error {
+	states := tx.Bucket(statesBucket)
+	for _, k := range legacyKeys(states) {
+		if err := moveValue(states, k, nil); err != nil {
+			return err
+		}
+	}
+
+	policies := tx.Bucket(policiesBucket)
+	for _, k := range legacyKeys(policies) {
+		err := moveValue(policies, k, func(val []byte) ([]byte, error) {
+			var rec storage.PolicyRecord
+			if err := json.Unmarshal(val, &rec); err != nil {
+				return nil, err
+			}
+			rec.Tenant = storage.DefaultTenant
+			return json.Marshal(&rec)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	history := tx.Bucket(historyBucket)
+	for _, k := range legacyKeys(history) {
+		if err := moveBucket(history, k); err != nil {
+			return err
+		}
+	}
+
+	checkpoints := tx.Bucket(checkpointsBucket)
+	return checkpoints.ForEach(func(name, val []byte) error {
+		qualified, err := qualifyWindows(val)
+		if err != nil || qualified == nil {
+			return err
+		}
+		return checkpoints.Put(name, qualified)
+	})
+}
+
+// legacyKeys returns the keys in b that name no tenant.
+func legacyKeys(b *bbolt.Bucket) [][]byte {
+	var keys [][]byte
+	b.ForEach(func(k, _ []byte) error {
+		if !bytes.ContainsRune(k, '/') {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	return keys
+}
+
+func moveValue(b *bbolt.Bucket, legacy []byte, conv func([]byte) ([]byte, error)) error {
+	key := []byte(storage.Key(storage.DefaultTenant, string(legacy)))
+	if b.Get(key) != nil {
+		return fmt.Errorf("%s already exists, legacy key %s left in place", key, legacy)
+	}
+
+	val := b.Get(legacy)
+	if conv != nil {
+		var err error
+		if val, err = conv(val); err != nil {
+			return fmt.Errorf("%s: %w", legacy, err)
+		}
+	}
+	if err := b.Put(key, val); err != nil {
+		return err
+	}
+	return b.Delete(legacy)
+}
+
+// moveBucket moves the nested bucket legacy to the default tenant's key,
+// keeping its entries' sequence numbers.
+func moveBucket(parent *bbolt.Bucket, legacy []byte) error {
+	key := []byte(storage.Key(storage.DefaultTenant, string(legacy)))
+	if parent.Bucket(key) != nil {
+		return fmt.Errorf("history %s already exists, legacy history %s left in place", key, legacy)
+	}
+
+	from := parent.Bucket(legacy)
+	to, err := parent.CreateBucket(key)
+	if err != nil {
+		return err
+	}
+	if err := from.ForEach(to.Put); err != nil {
+		return err
+	}
+	if err := to.SetSequence(from.Sequence()); err != nil {
+		return err
+	}
+	return parent.DeleteBucket(legacy)
+}
+
+// qualifyWindows returns the checkpoint val with the windows keyed by a
+// bare service moved to the default tenant, or nil if there are none.
+func qualifyWindows(val []byte) ([]byte, error) {
+	var cp storage.Checkpoint
+	if err := json.Unmarshal(val, &cp); err != nil {
+		return nil, err
+	}
+	legacy := false
+	windows := make(map[string]json.RawMessage, len(cp.Windows))
+	for key, w := range cp.Windows {
+		if !strings.Contains(key, "/") {
+			key = storage.Key(storage.DefaultTenant, key)
+			legacy = true
+		}
+		windows[key] = w
+	}
+	if !legacy {
+		return nil, nil
+	}
+	cp.Windows = windows
+	return json.Marshal(&cp)
+}
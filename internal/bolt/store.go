@@ -0,0 +1,783 @@
+// Package bolt is an embedded, single-file storage backend built on bbolt,
+// for running the control plane as one binary without Redis. Policy
+// change notifications only reach subscribers in the same process.
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+var (
+	statesBucket    = []byte("states")
+	policiesBucket  = []byte("policies")
+	decisionsBucket = []byte("decisions")
+	// decisionExpiryBucket indexes window claims by expiry: keys are the
+	// big-endian expiry (unix ms) followed by the claim's key.
+	decisionExpiryBucket = []byte("decision-expiry")
+	historyBucket        = []byte("history")
+	// decisionIndexBucket maps each service's decision sequence numbers
+	// to the keys of the history entries announcing them.
+	decisionIndexBucket = []byte("decision-index")
+	leasesBucket        = []byte("leases")
+	checkpointsBucket   = []byte("checkpoints")
+	outboxBucket        = []byte("outbox")
+)
+
+type Store struct {
+	db        *bbolt.DB
+	retention storage.HistoryRetention
+	notifier  storage.Notifier
+	// decisions only reach this process; bbolt has no pub/sub.
+	decisions storage.Broadcaster
+}
+
+var _ storage.Store = (*Store)(nil)
+
+// Open opens or creates the database file at path.
+func Open(path string) (*Store, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{statesBucket, policiesBucket, decisionsBucket, historyBucket, leasesBucket, checkpointsBucket, outboxBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if err := migrateLegacyKeys(tx); err != nil {
+			return err
+		}
+		if tx.Bucket(decisionIndexBucket) == nil {
+			if err := indexDecisions(tx); err != nil {
+				return err
+			}
+		}
+		if tx.Bucket(decisionExpiryBucket) == nil {
+			return indexClaims(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Get(ctx context.Context, key string) (*storage.State, error) {
+	var st *storage.State
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		st, err = getState(tx, key)
+		return err
+	})
+	return st, err
+}
+
+func getState(tx *bbolt.Tx, key string) (*storage.State, error) {
+	val := tx.Bucket(statesBucket).Get([]byte(key))
+	if val == nil {
+		return nil, nil
+	}
+
+	var st storage.State
+	if err := json.Unmarshal(val, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// ListRollouts walks the states bucket, which bbolt keeps in key order.
+func (s *Store) ListRollouts(
+	ctx context.Context,
+	f storage.RolloutFilter,
+	after string,
+	limit int64,
+) ([]*storage.State, string, error) {
+	if limit <= 0 {
+		return nil, "", nil
+	}
+	var (
+		out  []*storage.State
+		next string
+	)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(statesBucket).Cursor()
+		k, v := c.First()
+		if after != "" {
+			if k, v = c.Seek([]byte(after)); k != nil && string(k) == after {
+				k, v = c.Next()
+			}
+		}
+		for ; k != nil; k, v = c.Next() {
+			var st storage.State
+			if err := json.Unmarshal(v, &st); err != nil {
+				return err
+			}
+			if !f.Match(&st) {
+				continue
+			}
+			if int64(len(out)) == limit {
+				next = out[len(out)-1].Key()
+				return nil
+			}
+			out = append(out, &st)
+		}
+		return nil
+	})
+	return out, next, err
+}
+
+func (s *Store) Save(ctx context.Context, st *storage.State) error {
+	next := *st
+	next.Revision = st.Revision + 1
+	next.LastUpdated = time.Now().UnixMilli()
+	next.Outbox = nil
+	next.History = nil
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		if err := checkFence(ctx, tx); err != nil {
+			return err
+		}
+
+		cur, err := getState(tx, st.Key())
+		if err != nil {
+			return err
+		}
+		var rev int64
+		if cur != nil {
+			rev = cur.Revision
+		}
+		if rev != st.Revision {
+			return &storage.ConflictError{Key: st.Key(), Expected: st.Revision, Actual: rev}
+		}
+
+		if err := appendOutbox(tx, st.Key(), st.Outbox); err != nil {
+			return err
+		}
+		for i := range st.History {
+			if err := s.appendHistory(tx, &st.History[i]); err != nil {
+				return err
+			}
+		}
+		bytes, _ := json.Marshal(&next)
+		return tx.Bucket(statesBucket).Put([]byte(st.Key()), bytes)
+	})
+	if err != nil {
+		return err
+	}
+
+	*st = next
+	return nil
+}
+
+// appendOutbox keys a service's outbox by sequence number, like its
+// history.
+func appendOutbox(tx *bbolt.Tx, key string, msgs []storage.OutboxMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	b, err := tx.Bucket(outboxBucket).CreateBucketIfNotExists([]byte(key))
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		bytes, _ := json.Marshal(&msg)
+		if err := b.Put(u64(seq), bytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) OutboxKeys(ctx context.Context) ([]string, error) {
+	var keys []string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(outboxBucket).ForEachBucket(func(k []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	return keys, err
+}
+
+func (s *Store) PendingOutbox(ctx context.Context, key string, limit int64) ([]*storage.OutboxMessage, error) {
+	var msgs []*storage.OutboxMessage
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(outboxBucket).Bucket([]byte(key))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.First(); k != nil && int64(len(msgs)) < limit; k, v = c.Next() {
+			var msg storage.OutboxMessage
+			if err := json.Unmarshal(v, &msg); err != nil {
+				return err
+			}
+			msg.ID = strconv.FormatUint(binary.BigEndian.Uint64(k), 10)
+			msgs = append(msgs, &msg)
+		}
+		return nil
+	})
+	return msgs, err
+}
+
+// AckOutbox drops the service's outbox bucket once it is empty.
+func (s *Store) AckOutbox(ctx context.Context, key, id string) error {
+	seq, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		outbox := tx.Bucket(outboxBucket)
+		b := outbox.Bucket([]byte(key))
+		if b == nil {
+			return nil
+		}
+		if err := b.Delete(u64(seq)); err != nil {
+			return err
+		}
+		if k, _ := b.Cursor().First(); k == nil {
+			return outbox.DeleteBucket([]byte(key))
+		}
+		return nil
+	})
+}
+
+// windowClaim is a recorded window decision with its expiry.
+type windowClaim struct {
+	Decision storage.WindowDecision `json:"decision"`
+	Expires  int64                  `json:"expires"`
+}
+
+// claimSweepLimit bounds how many expired claims one ClaimWindow deletes,
+// so its cost does not grow with the number of claims kept.
+const claimSweepLimit = 64
+
+// ClaimWindow sweeps the oldest expired claims as it goes.
+func (s *Store) ClaimWindow(
+	ctx context.Context,
+	key, windowID string,
+	d *storage.WindowDecision,
+	ttl time.Duration,
+) (*storage.WindowDecision, bool, error) {
+	claim := []byte(key + ":" + windowID)
+	now := time.Now().UnixMilli()
+
+	won, claimed := d, false
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		b, expiry := tx.Bucket(decisionsBucket), tx.Bucket(decisionExpiryBucket)
+		if err := sweepClaims(b, expiry, now); err != nil {
+			return err
+		}
+
+		if val := b.Get(claim); val != nil {
+			var c windowClaim
+			if err := json.Unmarshal(val, &c); err != nil {
+				return err
+			}
+			if c.Expires > now {
+				won = &c.Decision
+				return nil
+			}
+			// Expired but not swept yet.
+			if err := expiry.Delete(expiryKey(c.Expires, claim)); err != nil {
+				return err
+			}
+		}
+		claimed = true
+		expires := now + ttl.Milliseconds()
+		bytes, _ := json.Marshal(windowClaim{Decision: *d, Expires: expires})
+		if err := b.Put(claim, bytes); err != nil {
+			return err
+		}
+		return expiry.Put(expiryKey(expires, claim), nil)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return won, claimed, nil
+}
+
+func (s *Store) SetHistoryRetention(r storage.HistoryRetention) {
+	s.retention = r
+}
+
+// AppendHistory keys entries by a per-service sequence number, which is
+// also the entry ID.
+func (s *Store) AppendHistory(ctx context.Context, e *storage.HistoryEntry) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return s.appendHistory(tx, e)
+	})
+}
+
+func (s *Store) appendHistory(tx *bbolt.Tx, e *storage.HistoryEntry) error {
+	if e.Timestamp == 0 {
+		e.Timestamp = time.Now().UnixMilli()
+	}
+	bytes, _ := json.Marshal(e)
+
+	b, err := tx.Bucket(historyBucket).CreateBucketIfNotExists([]byte(e.Key()))
+	if err != nil {
+		return err
+	}
+	seq, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	if err := b.Put(u64(seq), bytes); err != nil {
+		return err
+	}
+	e.ID = strconv.FormatUint(seq, 10)
+
+	if err := s.trim(b, seq, e.Timestamp); err != nil {
+		return err
+	}
+	if e.Sequence == 0 {
+		return nil
+	}
+	index, err := tx.Bucket(decisionIndexBucket).CreateBucketIfNotExists([]byte(e.Key()))
+	if err != nil {
+		return err
+	}
+	if err := index.Put(u64(uint64(e.Sequence)), u64(seq)); err != nil {
+		return err
+	}
+	return trimIndex(index, b)
+}
+
+// trimIndex deletes the oldest index entries whose history entry has
+// been trimmed.
+func trimIndex(index, history *bbolt.Bucket) error {
+	var stale [][]byte
+	c := index.Cursor()
+	for k, v := c.First(); k != nil && history.Get(v) == nil; k, v = c.Next() {
+		stale = append(stale, append([]byte(nil), k...))
+	}
+	return deleteKeys(index, stale)
+}
+
+// indexDecisions builds the decision index for history written before it
+// existed.
+func indexDecisions(tx *bbolt.Tx) error {
+	indexes, err := tx.CreateBucket(decisionIndexBucket)
+	if err != nil {
+		return err
+	}
+	history := tx.Bucket(historyBucket)
+	return history.ForEachBucket(func(key []byte) error {
+		var index *bbolt.Bucket
+		return history.Bucket(key).ForEach(func(k, v []byte) error {
+			var e storage.HistoryEntry
+			if err := json.Unmarshal(v, &e); err != nil || e.Sequence == 0 {
+				return nil
+			}
+			if index == nil {
+				if index, err = indexes.CreateBucket(key); err != nil {
+					return err
+				}
+			}
+			return index.Put(u64(uint64(e.Sequence)), k)
+		})
+	})
+}
+
+// trim deletes the oldest entries outside the retention; seq is the
+// newest entry's key.
+func (s *Store) trim(b *bbolt.Bucket, seq uint64, now int64) error {
+	var minSeq uint64
+	if max := s.retention.MaxLen; max > 0 && seq > uint64(max) {
+		minSeq = seq - uint64(max) + 1
+	}
+	cutoff := int64(-1)
+	if s.retention.MaxAge > 0 {
+		cutoff = now - s.retention.MaxAge.Milliseconds()
+	}
+
+	var stale [][]byte
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if binary.BigEndian.Uint64(k) >= minSeq {
+			var e storage.HistoryEntry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if e.Timestamp >= cutoff {
+				break
+			}
+		}
+		stale = append(stale, append([]byte(nil), k...))
+	}
+	return deleteKeys(b, stale)
+}
+
+// expiryKey orders claims in the expiry bucket by their expiry time.
+func expiryKey(expires int64, claim []byte) []byte {
+	k := make([]byte, 8, 8+len(claim))
+	binary.BigEndian.PutUint64(k, uint64(max(expires, 0)))
+	return append(k, claim...)
+}
+
+// sweepClaims deletes up to claimSweepLimit claims that expired by now,
+// oldest first.
+func sweepClaims(claims, expiry *bbolt.Bucket, now int64) error {
+	var swept [][]byte
+	c := expiry.Cursor()
+	for k, _ := c.First(); k != nil && len(swept) < claimSweepLimit; k, _ = c.Next() {
+		if int64(binary.BigEndian.Uint64(k[:8])) > now {
+			break
+		}
+		swept = append(swept, append([]byte(nil), k...))
+	}
+	for _, k := range swept {
+		if err := claims.Delete(k[8:]); err != nil {
+			return err
+		}
+	}
+	return deleteKeys(expiry, swept)
+}
+
+// indexClaims builds the expiry index for claims written before it
+// existed; unreadable claims are dropped.
+func indexClaims(tx *bbolt.Tx) error {
+	expiry, err := tx.CreateBucket(decisionExpiryBucket)
+	if err != nil {
+		return err
+	}
+	claims := tx.Bucket(decisionsBucket)
+
+	var unreadable [][]byte
+	err = claims.ForEach(func(k, v []byte) error {
+		var c windowClaim
+		if err := json.Unmarshal(v, &c); err != nil {
+			unreadable = append(unreadable, append([]byte(nil), k...))
+			return nil
+		}
+		return expiry.Put(expiryKey(c.Expires, k), nil)
+	})
+	if err != nil {
+		return err
+	}
+	return deleteKeys(claims, unreadable)
+}
+
+// deleteKeys deletes outside of any cursor walk, which bbolt does not
+// support reliably.
+func deleteKeys(b *bbolt.Bucket, keys [][]byte) error {
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) History(
+	ctx context.Context,
+	key string,
+	from, to int64,
+	after string,
+	limit int64,
+) (entries []*storage.HistoryEntry, next string, err error) {
+	var start uint64
+	if after != "" {
+		seq, err := strconv.ParseUint(after, 10, 64)
+		if err != nil {
+			return nil, "", storage.ErrInvalidPageToken
+		}
+		start = seq + 1
+	}
+
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(historyBucket).Bucket([]byte(key))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		for k, v := c.Seek(u64(start)); k != nil; k, v = c.Next() {
+			var e storage.HistoryEntry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if (from > 0 && e.Timestamp < from) || (to > 0 && e.Timestamp > to) {
+				continue
+			}
+			if int64(len(entries)) == limit {
+				next = entries[len(entries)-1].ID
+				return nil
+			}
+			e.ID = strconv.FormatUint(binary.BigEndian.Uint64(k), 10)
+			entries = append(entries, &e)
+		}
+		return nil
+	})
+	return entries, next, err
+}
+
+func (s *Store) DecisionHistory(ctx context.Context, key string, from, limit int64) ([]*storage.HistoryEntry, error) {
+	var entries []*storage.HistoryEntry
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		index := tx.Bucket(decisionIndexBucket).Bucket([]byte(key))
+		history := tx.Bucket(historyBucket).Bucket([]byte(key))
+		if index == nil || history == nil {
+			return nil
+		}
+
+		c := index.Cursor()
+		for k, v := c.Seek(u64(uint64(from))); k != nil && int64(len(entries)) < limit; k, v = c.Next() {
+			val := history.Get(v)
+			if val == nil || (len(entries) == 0 && binary.BigEndian.Uint64(k) != uint64(from)) {
+				return storage.ErrHistoryTrimmed
+			}
+			var e storage.HistoryEntry
+			if err := json.Unmarshal(val, &e); err != nil {
+				return err
+			}
+			e.ID = strconv.FormatUint(binary.BigEndian.Uint64(v), 10)
+			entries = append(entries, &e)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+func (s *Store) PutPolicy(ctx context.Context, key, spec string, expectedVersion int64) (*storage.PolicyRecord, error) {
+	tenant, service := storage.SplitKey(key)
+	rec := &storage.PolicyRecord{
+		Tenant:    tenant,
+		ServiceID: service,
+		Version:   expectedVersion + 1,
+		Spec:      spec,
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		cur, err := getPolicy(tx, key)
+		if err != nil {
+			return err
+		}
+		var ver int64
+		if cur != nil {
+			ver = cur.Version
+		}
+		if ver != expectedVersion {
+			return storage.ErrVersionConflict
+		}
+
+		bytes, _ := json.Marshal(rec)
+		return tx.Bucket(policiesBucket).Put([]byte(key), bytes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifier.Notify(key)
+	return rec, nil
+}
+
+func (s *Store) GetPolicy(ctx context.Context, key string) (*storage.PolicyRecord, error) {
+	var rec *storage.PolicyRecord
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		rec, err = getPolicy(tx, key)
+		return err
+	})
+	return rec, err
+}
+
+func getPolicy(tx *bbolt.Tx, key string) (*storage.PolicyRecord, error) {
+	val := tx.Bucket(policiesBucket).Get([]byte(key))
+	if val == nil {
+		return nil, nil
+	}
+
+	var rec storage.PolicyRecord
+	if err := json.Unmarshal(val, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (s *Store) ListPolicies(ctx context.Context) ([]*storage.PolicyRecord, error) {
+	var records []*storage.PolicyRecord
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(policiesBucket).ForEach(func(k, v []byte) error {
+			var rec storage.PolicyRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			records = append(records, &rec)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key() < records[j].Key()
+	})
+	return records, nil
+}
+
+func (s *Store) DeletePolicy(ctx context.Context, key string, expectedVersion int64) (bool, error) {
+	var deleted bool
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		cur, err := getPolicy(tx, key)
+		if err != nil || cur == nil {
+			return err
+		}
+		if cur.Version != expectedVersion {
+			return storage.ErrVersionConflict
+		}
+		deleted = true
+		return tx.Bucket(policiesBucket).Delete([]byte(key))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if deleted {
+		s.notifier.Notify(key)
+	}
+	return deleted, nil
+}
+
+func (s *Store) SubscribePolicies(ctx context.Context) (<-chan string, error) {
+	return s.notifier.Subscribe(ctx), nil
+}
+
+func (s *Store) PublishDecision(ctx context.Context, payload []byte) error {
+	s.decisions.Broadcast(payload)
+	return nil
+}
+
+func (s *Store) SubscribeDecisions(ctx context.Context) (<-chan []byte, error) {
+	return s.decisions.Subscribe(ctx), nil
+}
+
+// lease is kept after release or expiry so tokens keep increasing.
+type lease struct {
+	Holder  string `json:"holder"`
+	Token   int64  `json:"token"`
+	Expires int64  `json:"expires"`
+}
+
+func getLease(tx *bbolt.Tx, name string) (lease, error) {
+	var l lease
+	val := tx.Bucket(leasesBucket).Get([]byte(name))
+	if val == nil {
+		return l, nil
+	}
+	err := json.Unmarshal(val, &l)
+	return l, err
+}
+
+func putLease(tx *bbolt.Tx, name string, l lease) error {
+	bytes, _ := json.Marshal(l)
+	return tx.Bucket(leasesBucket).Put([]byte(name), bytes)
+}
+
+// checkFence fails with ErrFenced if ctx carries a stale token.
+func checkFence(ctx context.Context, tx *bbolt.Tx) error {
+	f, ok := storage.FenceFrom(ctx)
+	if !ok {
+		return nil
+	}
+	l, err := getLease(tx, f.Lease)
+	if err != nil {
+		return err
+	}
+	if l.Token != f.Token || l.Holder == "" || time.Now().UnixMilli() >= l.Expires {
+		return storage.ErrFenced
+	}
+	return nil
+}
+
+func (s *Store) SaveCheckpoint(ctx context.Context, name string, cp *storage.Checkpoint) error {
+	bytes, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		if err := checkFence(ctx, tx); err != nil {
+			return err
+		}
+		return tx.Bucket(checkpointsBucket).Put([]byte(name), bytes)
+	})
+}
+
+func (s *Store) LoadCheckpoint(ctx context.Context, name string) (*storage.Checkpoint, error) {
+	var cp *storage.Checkpoint
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		val := tx.Bucket(checkpointsBucket).Get([]byte(name))
+		if val == nil {
+			return nil
+		}
+		cp = &storage.Checkpoint{}
+		return json.Unmarshal(val, cp)
+	})
+	return cp, err
+}
+
+func (s *Store) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (int64, error) {
+	now := time.Now().UnixMilli()
+
+	var token int64
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		l, err := getLease(tx, name)
+		if err != nil {
+			return err
+		}
+		live := l.Holder != "" && now < l.Expires
+		switch {
+		case live && l.Holder != holder:
+			return nil
+		case !live:
+			l.Holder = holder
+			l.Token++
+		}
+		l.Expires = now + ttl.Milliseconds()
+		token = l.Token
+		return putLease(tx, name, l)
+	})
+	return token, err
+}
+
+func (s *Store) ReleaseLease(ctx context.Context, name, holder string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		l, err := getLease(tx, name)
+		if err != nil || l.Holder != holder {
+			return err
+		}
+		l.Holder = ""
+		return putLease(tx, name, l)
+	})
+}
+
+func u64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
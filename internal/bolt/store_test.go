@@ -0,0 +1,367 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+func TestStateSurvivesReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "canary.db")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &storage.State{ServiceID: "checkout-service", State: storage.Canary, TrafficWeight: 10}
+	if err := s.Save(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.PutPolicy(ctx, "default/checkout-service", "service: checkout-service", 0); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	got, err := s.Get(ctx, "default/checkout-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Revision != 1 || got.TrafficWeight != 10 {
+		t.Fatalf("state not persisted: %+v", got)
+	}
+	if rec, _ := s.GetPolicy(ctx, "default/checkout-service"); rec == nil || rec.Version != 1 {
+		t.Fatalf("policy not persisted: %+v", rec)
+	}
+
+	stale := &storage.State{ServiceID: "checkout-service"}
+	if err := s.Save(ctx, stale); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+}
+
+func TestClaimWindowKeepsFirstDecision(t *testing.T) {
+	ctx := context.Background()
+	s, err := Open(filepath.Join(t.TempDir(), "canary.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	promote := &storage.WindowDecision{Decision: "PROMOTE", Reason: "healthy"}
+	rollback := &storage.WindowDecision{Decision: "ROLLBACK", Reason: "error rate"}
+
+	if _, won, _ := s.ClaimWindow(ctx, "default/checkout-service", "w1", promote, time.Minute); !won {
+		t.Fatal("expected the first claim to win")
+	}
+	got, won, err := s.ClaimWindow(ctx, "default/checkout-service", "w1", rollback, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if won || got.Decision != "PROMOTE" {
+		t.Fatalf("expected the recorded PROMOTE back, got %+v (won %v)", got, won)
+	}
+	if _, won, _ := s.ClaimWindow(ctx, "default/checkout-service", "w2", rollback, time.Minute); !won {
+		t.Fatal("expected a new window to be claimable")
+	}
+	if _, won, _ := s.ClaimWindow(ctx, "default/checkout-service", "w3", rollback, -time.Second); !won {
+		t.Fatal("expected w3 to be claimable")
+	}
+	if _, won, _ := s.ClaimWindow(ctx, "default/checkout-service", "w3", promote, time.Minute); !won {
+		t.Fatal("expected an expired claim to be replaced")
+	}
+}
+
+func TestClaimWindowSweepsExpiredClaimsInBoundedBatches(t *testing.T) {
+	ctx := context.Background()
+	s, err := Open(filepath.Join(t.TempDir(), "canary.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// A backlog of expired claims, as left by a long idle period.
+	expired := time.Now().Add(-time.Hour).UnixMilli()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		for i := 0; i < claimSweepLimit+10; i++ {
+			claim := []byte(fmt.Sprintf("default/checkout-service:old-%d", i))
+			bytes, _ := json.Marshal(windowClaim{Expires: expired})
+			if err := tx.Bucket(decisionsBucket).Put(claim, bytes); err != nil {
+				return err
+			}
+			if err := tx.Bucket(decisionExpiryBucket).Put(expiryKey(expired, claim), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := func() (claims, indexed int) {
+		s.db.View(func(tx *bbolt.Tx) error {
+			claims = tx.Bucket(decisionsBucket).Stats().KeyN
+			indexed = tx.Bucket(decisionExpiryBucket).Stats().KeyN
+			return nil
+		})
+		return claims, indexed
+	}
+
+	d := &storage.WindowDecision{Decision: "PROMOTE"}
+	if _, _, err := s.ClaimWindow(ctx, "default/checkout-service", "w1", d, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if claims, indexed := count(); claims != 11 || indexed != 11 {
+		t.Fatalf("expected one bounded sweep to leave 10 expired claims and w1, got %d (%d indexed)", claims, indexed)
+	}
+	if _, _, err := s.ClaimWindow(ctx, "default/checkout-service", "w2", d, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if claims, indexed := count(); claims != 2 || indexed != 2 {
+		t.Fatalf("expected only the live claims to remain, got %d (%d indexed)", claims, indexed)
+	}
+}
+
+func TestHistoryRetentionAndPaging(t *testing.T) {
+	ctx := context.Background()
+	s, err := Open(filepath.Join(t.TempDir(), "canary.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.SetHistoryRetention(storage.HistoryRetention{MaxLen: 3})
+
+	for i := 0; i < 5; i++ {
+		err := s.AppendHistory(ctx, &storage.HistoryEntry{
+			ServiceID: "checkout-service",
+			Kind:      storage.HistoryDecision,
+			Timestamp: int64(1000 + i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, next, err := s.History(ctx, "default/checkout-service", 0, 0, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].Timestamp != 1002 || next == "" {
+		t.Fatalf("expected 2 of the 3 retained entries, got %d (next %q)", len(page), next)
+	}
+
+	page, next, _ = s.History(ctx, "default/checkout-service", 0, 0, next, 2)
+	if len(page) != 1 || page[0].Timestamp != 1004 || next != "" {
+		t.Fatalf("expected the newest entry and no token, got %d (next %q)", len(page), next)
+	}
+}
+
+func TestLeaseTokensIncreaseAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "canary.db")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := s.AcquireLease(ctx, "engine", "a", time.Minute)
+	if renewed, _ := s.AcquireLease(ctx, "engine", "a", time.Minute); renewed != first {
+		t.Fatalf("renewal must keep the token, got %d then %d", first, renewed)
+	}
+	s.ReleaseLease(ctx, "engine", "a")
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	second, _ := s.AcquireLease(ctx, "engine", "b", time.Minute)
+	if second <= first {
+		t.Fatalf("expected a larger token after reopen, got %d after %d", second, first)
+	}
+	err = s.Save(storage.WithFence(ctx, "engine", first), &storage.State{ServiceID: "checkout-service"})
+	if !errors.Is(err, storage.ErrFenced) {
+		t.Fatalf("expected a stale token to be fenced, got %v", err)
+	}
+}
+
+func TestOutboxSurvivesReopenUntilAcked(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "canary.db")
+	key := storage.Key("", "checkout-service")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &storage.State{ServiceID: "checkout-service", Outbox: []storage.OutboxMessage{
+		{DecisionID: "d1", Payload: []byte("one")},
+		{DecisionID: "d2", Payload: []byte("two")},
+	}}
+	if err := s.Save(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if keys, _ := s.OutboxKeys(ctx); len(keys) != 1 || keys[0] != key {
+		t.Fatalf("expected one outbox for %s, got %v", key, keys)
+	}
+	msgs, err := s.PendingOutbox(ctx, key, 1)
+	if err != nil || len(msgs) != 1 || msgs[0].DecisionID != "d1" {
+		t.Fatalf("expected the oldest decision first, got %+v, %v", msgs, err)
+	}
+
+	s.AckOutbox(ctx, key, msgs[0].ID)
+	msgs, _ = s.PendingOutbox(ctx, key, 10)
+	if len(msgs) != 1 || msgs[0].DecisionID != "d2" {
+		t.Fatalf("expected only d2 left, got %+v", msgs)
+	}
+	s.AckOutbox(ctx, key, msgs[0].ID)
+	if keys, _ := s.OutboxKeys(ctx); len(keys) != 0 {
+		t.Fatalf("an empty outbox should be dropped, got %v", keys)
+	}
+}
+
+func TestOpenMovesLegacyKeysToDefaultTenant(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "canary.db")
+
+	db, err := bbolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		put := func(bucket, key string, v any) error {
+			b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+			if err != nil {
+				return err
+			}
+			val, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return b.Put([]byte(key), val)
+		}
+		if err := put("states", "checkout-service", &storage.State{ServiceID: "checkout-service", State: storage.Canary}); err != nil {
+			return err
+		}
+		if err := put("policies", "checkout-service", &storage.PolicyRecord{ServiceID: "checkout-service", Version: 3}); err != nil {
+			return err
+		}
+		if err := put("checkpoints", "decision-engine", &storage.Checkpoint{
+			Windows: map[string]json.RawMessage{"checkout-service": json.RawMessage("[]")},
+		}); err != nil {
+			return err
+		}
+
+		history, err := tx.CreateBucketIfNotExists([]byte("history"))
+		if err != nil {
+			return err
+		}
+		b, err := history.CreateBucket([]byte("checkout-service"))
+		if err != nil {
+			return err
+		}
+		val, _ := json.Marshal(&storage.HistoryEntry{ServiceID: "checkout-service", Timestamp: 1000})
+		if err := b.Put(u64(1), val); err != nil {
+			return err
+		}
+		return b.SetSequence(1)
+	})
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key := "default/checkout-service"
+	if st, err := s.Get(ctx, key); err != nil || st == nil || st.State != storage.Canary {
+		t.Fatalf("expected the legacy rollout under %s, got %+v (%v)", key, st, err)
+	}
+	if rec, err := s.GetPolicy(ctx, key); err != nil || rec == nil || rec.Version != 3 || rec.Tenant != storage.DefaultTenant {
+		t.Fatalf("expected the legacy policy under %s, got %+v (%v)", key, rec, err)
+	}
+	if cp, err := s.LoadCheckpoint(ctx, "decision-engine"); err != nil || cp.Windows[key] == nil {
+		t.Fatalf("expected the checkpointed window under %s, got %+v (%v)", key, cp, err)
+	}
+
+	if err := s.AppendHistory(ctx, &storage.HistoryEntry{ServiceID: "checkout-service", Timestamp: 2000}); err != nil {
+		t.Fatal(err)
+	}
+	page, _, err := s.History(ctx, key, 0, 0, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].Timestamp != 1000 || page[1].Timestamp != 2000 {
+		t.Fatalf("expected the legacy entry followed by the new one, got %+v", page)
+	}
+}
+
+func TestDecisionHistoryIsIndexedAndTrimmed(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "canary.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetHistoryRetention(storage.HistoryRetention{MaxLen: 3})
+	key := "default/checkout-service"
+
+	st := &storage.State{ServiceID: "checkout-service"}
+	for seq := int64(1); seq <= 4; seq++ {
+		st.Sequence = seq
+		st.History = []storage.HistoryEntry{{ServiceID: "checkout-service", Sequence: seq}}
+		if err := s.Save(ctx, st); err != nil {
+			t.Fatal(err)
+		}
+		if seq == 2 {
+			s.AppendHistory(ctx, &storage.HistoryEntry{ServiceID: "checkout-service", Kind: storage.HistoryApproval})
+		}
+	}
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	entries, err := s.DecisionHistory(ctx, key, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Sequence != 3 || entries[1].Sequence != 4 {
+		t.Fatalf("expected decisions 3 and 4, got %+v", entries)
+	}
+	if _, err := s.DecisionHistory(ctx, key, 2, 10); !errors.Is(err, storage.ErrHistoryTrimmed) {
+		t.Fatalf("expected decision 2 to be trimmed, got %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+)
+
+// BayesianConfig tunes the Bayesian analysis mode. Error rates are
+// modelled as Beta posteriors per track; latency as log-normal, so the
+// latency margin is a relative slowdown of the median.
+type BayesianConfig struct {
+	// ErrorMargin is the absolute error-rate increase over baseline that
+	// is tolerated, e.g. 0.01.
+	ErrorMargin float64 `yaml:"error_margin"`
+	// LatencyMargin is the tolerated relative slowdown, e.g. 0.1 for 10%.
+	LatencyMargin float64 `yaml:"latency_margin"`
+
+	// RollbackProbability: roll back once P(canary worse by more than the
+	// margin) reaches it. PromoteProbability: promote once P(not worse)
+	// reaches it. Anything in between pauses.
+	RollbackProbability float64 `yaml:"rollback_probability"`
+	PromoteProbability  float64 `yaml:"promote_probability"`
+
+	// PriorStrength is the weight, in pseudo-requests, of the Beta prior
+	// on error rates. The prior is centred on the baseline error rate (or
+	// thresholds.error_rate without stable traffic) so that a handful of
+	// canary requests cannot swing the verdict. Default 10.
+	PriorStrength float64 `yaml:"prior_strength,omitempty"`
+}
+
+func (c BayesianConfig) validate() error {
+	if c.RollbackProbability <= 0 || c.RollbackProbability > 1 ||
+		c.PromoteProbability <= 0 || c.PromoteProbability > 1 {
+		return fmt.Errorf("bayesian rollback_probability and promote_probability must be in (0, 1]")
+	}
+	if c.ErrorMargin < 0 || c.LatencyMargin < 0 || c.PriorStrength < 0 {
+		return fmt.Errorf("bayesian margins and prior_strength must not be negative")
+	}
+	return nil
+}
+
+func (c BayesianConfig) prior(center float64) (float64, float64) {
+	strength := c.PriorStrength
+	if strength == 0 {
+		strength = 10
+	}
+	center = math.Min(math.Max(center, 1e-4), 1-1e-4)
+	return strength * center, strength * (1 - center)
+}
+
+// bayesian returns P(canary is worse than baseline by more than the
+// tolerated margin), taking the worse of error rate and latency, and a
+// reason naming the signal that dominated. Without stable traffic the
+// policy's absolute thresholds stand in for the baseline.
+func (e *Engine) bayesian(events []Telemetry) (float64, string) {
+	cfg := e.Policy.Analysis.Bayesian
+
+	var (
+		cErr, cOK, bErr, bOK float64
+		cLog, bLog           []float64
+	)
+	for _, ev := range events {
+		y := math.Log(math.Max(ev.LatencyMs, 1e-3))
+		switch {
+		case ev.IsStable() && ev.IsError:
+			bErr++
+		case ev.IsStable():
+			bOK++
+		case ev.IsError:
+			cErr++
+		default:
+			cOK++
+		}
+		if ev.IsStable() {
+			bLog = append(bLog, y)
+		} else {
+			cLog = append(cLog, y)
+		}
+	}
+
+	var pErr float64
+	var errWhat string
+	if bErr+bOK > 0 {
+		alpha, beta := cfg.prior(bErr / (bErr + bOK))
+		pErr = probBetaDiffAbove(alpha+cErr, beta+cOK, alpha+bErr, beta+bOK, cfg.ErrorMargin)
+		errWhat = fmt.Sprintf("error rate worse than baseline by >%.3f", cfg.ErrorMargin)
+	} else {
+		alpha, beta := cfg.prior(e.Policy.Thresholds.ErrorRate)
+		pErr = 1 - betaCDF(e.Policy.Thresholds.ErrorRate+cfg.ErrorMargin, alpha+cErr, beta+cOK)
+		errWhat = fmt.Sprintf("error rate above %.3f", e.Policy.Thresholds.ErrorRate+cfg.ErrorMargin)
+	}
+
+	pLat, latWhat := -1.0, ""
+	if len(cLog) >= 2 {
+		cMean, cVar := meanVar(cLog)
+		logMargin := math.Log1p(cfg.LatencyMargin)
+		if len(bLog) >= 2 {
+			bMean, bVar := meanVar(bLog)
+			sd := math.Sqrt(cVar/float64(len(cLog)) + bVar/float64(len(bLog)))
+			pLat = probNormalAbove(cMean-bMean, sd, logMargin)
+			latWhat = fmt.Sprintf("median latency slower than baseline by >%.0f%%", cfg.LatencyMargin*100)
+		} else if e.Policy.Thresholds.LatencyMs > 0 {
+			sd := math.Sqrt(cVar / float64(len(cLog)))
+			pLat = probNormalAbove(cMean-math.Log(e.Policy.Thresholds.LatencyMs), sd, logMargin)
+			latWhat = fmt.Sprintf("median latency above %.0fms", e.Policy.Thresholds.LatencyMs*(1+cfg.LatencyMargin))
+		}
+	}
+
+	if pLat > pErr {
+		return pLat, fmt.Sprintf("P(%s) = %.3f", latWhat, pLat)
+	}
+	return pErr, fmt.Sprintf("P(%s) = %.3f", errWhat, pErr)
+}
+
+// probBetaDiffAbove computes P(X - Y > margin) for X ~ Beta(a1, b1) and
+// Y ~ Beta(a2, b2) by integrating Y's density over its bulk.
+func probBetaDiffAbove(a1, b1, a2, b2, margin float64) float64 {
+	mean := a2 / (a2 + b2)
+	sd := math.Sqrt(a2 * b2 / ((a2 + b2) * (a2 + b2) * (a2 + b2 + 1)))
+	lo := math.Max(0, mean-12*sd)
+	hi := math.Min(1, mean+12*sd)
+
+	const n = 2000
+	step := (hi - lo) / n
+	var p, mass float64
+	for i := 0; i < n; i++ {
+		y := lo + (float64(i)+0.5)*step
+		w := math.Exp(betaLogPDF(y, a2, b2)) * step
+		mass += w
+		if x := y + margin; x < 1 {
+			p += w * (1 - betaCDF(x, a1, b1))
+		}
+	}
+	if mass == 0 {
+		return 0
+	}
+	return clamp01(p / mass)
+}
+
+// probNormalAbove is P(Z > threshold) for Z ~ N(mean, sd²).
+func probNormalAbove(mean, sd, threshold float64) float64 {
+	if sd == 0 {
+		if mean > threshold {
+			return 1
+		}
+		return 0
+	}
+	return 0.5 * math.Erfc((threshold-mean)/(sd*math.Sqrt2))
+}
+
+func meanVar(xs []float64) (float64, float64) {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, ss / float64(len(xs)-1)
+}
+
+func betaLogPDF(x, a, b float64) float64 {
+	if x <= 0 || x >= 1 {
+		return math.Inf(-1)
+	}
+	return (a-1)*math.Log(x) + (b-1)*math.Log1p(-x) - logBeta(a, b)
+}
+
+func logBeta(a, b float64) float64 {
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	return la + lb - lab
+}
+
+// betaCDF is the regularized incomplete beta function I_x(a, b).
+func betaCDF(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	front := math.Exp(a*math.Log(x) + b*math.Log1p(-x) - logBeta(a, b))
+	if x < (a+1)/(a+b+2) {
+		return clamp01(front * betaContinuedFraction(x, a, b) / a)
+	}
+	return clamp01(1 - front*betaContinuedFraction(1-x, b, a)/b)
+}
+
+// betaContinuedFraction evaluates the continued fraction for I_x(a, b)
+// with the modified Lentz method.
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIter = 300
+		eps     = 1e-12
+		tiny    = 1e-300
+	)
+
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return h
+}
+
+func clamp01(p float64) float64 {
+	return math.Min(1, math.Max(0, p))
+}
@@ -0,0 +1,81 @@
+package decision
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBetaCDF(t *testing.T) {
+	cases := []struct{ x, a, b, want float64 }{
+		{0.3, 1, 1, 0.3},
+		{0.5, 2, 2, 0.5},
+		{0.2, 2, 1, 0.04},
+		{0.9, 1, 3, 0.999},
+	}
+	for _, c := range cases {
+		if got := betaCDF(c.x, c.a, c.b); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("I_%v(%v, %v) = %v, want %v", c.x, c.a, c.b, got, c.want)
+		}
+	}
+
+	if p := probBetaDiffAbove(50, 950, 50, 950, 0); math.Abs(p-0.5) > 0.01 {
+		t.Fatalf("identical posteriors should give ~0.5, got %v", p)
+	}
+}
+
+func bayesianPolicy() *Policy {
+	p := testPolicy()
+	p.Analysis.Mode = ModeBayesian
+	p.Analysis.Bayesian = BayesianConfig{
+		ErrorMargin:         0.01,
+		LatencyMargin:       0.1,
+		RollbackProbability: 0.95,
+		PromoteProbability:  0.95,
+	}
+	return p
+}
+
+func trackEvents(track string, n, errs int, latency float64) []Telemetry {
+	events := make([]Telemetry, 0, n)
+	for i := 0; i < n; i++ {
+		events = append(events, Telemetry{
+			ServiceID: "checkout-service",
+			LatencyMs: latency + float64(i%7),
+			IsError:   i < errs,
+			Track:     track,
+		})
+	}
+	return events
+}
+
+func TestBayesianVerdicts(t *testing.T) {
+	engine := NewEngine(bayesianPolicy())
+
+	// A handful of canary requests cannot settle anything either way.
+	events := append(trackEvents(TrackCanary, 5, 1, 100), trackEvents(TrackStable, 500, 5, 100)...)
+	v := engine.Evaluate(events)
+	if v.Decision != Pause {
+		t.Fatalf("expected PAUSE at low traffic, got %s (%s)", v.Decision, v.Reason)
+	}
+
+	// 10% vs 1% errors on real volume is conclusive.
+	events = append(trackEvents(TrackCanary, 500, 50, 100), trackEvents(TrackStable, 5000, 50, 100)...)
+	v = engine.Evaluate(events)
+	if v.Decision != Rollback || v.Probability < 0.95 {
+		t.Fatalf("expected ROLLBACK with p>=0.95, got %s p=%.3f (%s)", v.Decision, v.Probability, v.Reason)
+	}
+
+	// Matching error rate and latency on real volume promotes.
+	events = append(trackEvents(TrackCanary, 2000, 20, 100), trackEvents(TrackStable, 5000, 50, 100)...)
+	v = engine.Evaluate(events)
+	if v.Decision != Promote || v.Probability > 0.05 {
+		t.Fatalf("expected PROMOTE with p<=0.05, got %s p=%.3f (%s)", v.Decision, v.Probability, v.Reason)
+	}
+
+	// 50% slower median latency is caught even with clean error rates.
+	events = append(trackEvents(TrackCanary, 500, 0, 150), trackEvents(TrackStable, 5000, 0, 100)...)
+	v = engine.Evaluate(events)
+	if v.Decision != Rollback {
+		t.Fatalf("expected ROLLBACK on latency, got %s p=%.3f (%s)", v.Decision, v.Probability, v.Reason)
+	}
+}
@@ -0,0 +1,109 @@
+package decision
+
+import "fmt"
+
+const (
+	DependencyPause    = "PAUSE"
+	DependencySuppress = "SUPPRESS"
+)
+
+// DependencyFailures controls how rollbacks caused by a shared, degraded
+// downstream are handled.
+type DependencyFailures struct {
+	// Dominance is the share of canary errors one dependency must account
+	// for before the rollback is blamed on it (default 0.5).
+	Dominance float64 `yaml:"dominance,omitempty"`
+	// Action is PAUSE (default) to downgrade the rollback, or SUPPRESS to
+	// re-judge the window with that dependency's errors discounted.
+	Action string `yaml:"action,omitempty"`
+}
+
+func (d DependencyFailures) validate() error {
+	if d.Dominance < 0 || d.Dominance > 1 {
+		return fmt.Errorf("dependency_failures.dominance must be in [0, 1]")
+	}
+	switch d.Action {
+	case "", DependencyPause, DependencySuppress:
+		return nil
+	}
+	return fmt.Errorf("dependency_failures.action must be %s or %s", DependencyPause, DependencySuppress)
+}
+
+func (d DependencyFailures) dominance() float64 {
+	if d.Dominance > 0 {
+		return d.Dominance
+	}
+	return 0.5
+}
+
+// sharedDependency names the declared dependency that dominates the
+// canary's errors and that the stable track is failing on at a
+// comparable rate (at least half the canary's), or "" if there is none.
+func (e *Engine) sharedDependency(events []Telemetry) (string, string) {
+	if len(e.Policy.Dependencies) == 0 {
+		return "", ""
+	}
+
+	declared := make(map[string]bool, len(e.Policy.Dependencies))
+	for _, d := range e.Policy.Dependencies {
+		declared[d] = true
+	}
+
+	var canaryEvents, stableEvents, canaryErrors int
+	canaryByDep := make(map[string]int)
+	stableByDep := make(map[string]int)
+	for _, ev := range events {
+		if ev.IsStable() {
+			stableEvents++
+			if ev.IsError && declared[ev.Dependency] {
+				stableByDep[ev.Dependency]++
+			}
+			continue
+		}
+
+		canaryEvents++
+		if !ev.IsError {
+			continue
+		}
+		canaryErrors++
+		if declared[ev.Dependency] {
+			canaryByDep[ev.Dependency]++
+		}
+	}
+	if canaryErrors == 0 || stableEvents == 0 {
+		return "", ""
+	}
+
+	var top string
+	for _, dep := range e.Policy.Dependencies {
+		if canaryByDep[dep] > canaryByDep[top] {
+			top = dep
+		}
+	}
+	share := float64(canaryByDep[top]) / float64(canaryErrors)
+	if top == "" || share < e.Policy.DependencyFailures.dominance() {
+		return "", ""
+	}
+
+	canaryRate := float64(canaryByDep[top]) / float64(canaryEvents)
+	stableRate := float64(stableByDep[top]) / float64(stableEvents)
+	if stableRate < canaryRate/2 {
+		return "", ""
+	}
+
+	return top, fmt.Sprintf("%s causes %.0f%% of canary errors and fails %.1f%% of stable requests",
+		top, share*100, stableRate*100)
+}
+
+// withoutDependencyErrors returns a copy of events in which failures
+// attributed to dep no longer count as errors.
+func withoutDependencyErrors(events []Telemetry, dep string) []Telemetry {
+	out := make([]Telemetry, len(events))
+	copy(out, events)
+	for i := range out {
+		if out[i].IsError && out[i].Dependency == dep {
+			out[i].IsError = false
+		}
+	}
+	return out
+}
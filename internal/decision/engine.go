@@ -1,5 +1,7 @@
 package decision
 
+import "fmt"
+
 type DecisionType string
 
 const (
@@ -8,11 +10,52 @@ const (
 	Rollback DecisionType = "ROLLBACK"
 )
 
+const (
+	TrackCanary = "canary"
+	TrackStable = "stable"
+)
+
 type Telemetry struct {
 	ServiceID string
 	LatencyMs float64
 	IsError   bool
 	Timestamp int64
+	// Track is "canary" or "stable"; empty is treated as canary.
+	Track string
+	// Dependency names the downstream that caused a failed request, if
+	// the service could tell.
+	Dependency string
+	// Counters carries named business-event increments, e.g.
+	// payment_attempt / payment_success.
+	Counters map[string]float64
+}
+
+func (t Telemetry) IsStable() bool {
+	return t.Track == TrackStable
+}
+
+type Metrics struct {
+	CanaryEvents int
+	StableEvents int
+	ErrorRate    float64
+	AvgLatencyMs float64
+	CanaryRPS    float64
+	StableRPS    float64
+	Ratios       map[string]RatioValue
+}
+
+type Verdict struct {
+	Decision DecisionType
+	Reason   string
+	Metrics  Metrics
+	// Probability is the posterior probability that the canary is worse
+	// than tolerated; only set in bayesian mode.
+	Probability float64
+	// Pending means a sequential test has not concluded yet: the caller
+	// should keep accumulating events instead of acting on the verdict.
+	Pending bool
+	// SuppressedBy names the shared dependency a rollback was blamed on.
+	SuppressedBy string
 }
 
 type Engine struct {
@@ -25,31 +68,181 @@ func NewEngine(policy *Policy) *Engine {
 	}
 }
 
-func (e *Engine) Evaluate(events []Telemetry) DecisionType {
-	if len(events) == 0 {
-		return e.Policy.Actions.OnSuccess
+// Evaluate judges one window. Error rate and latency are computed over
+// canary events only; stable events feed the traffic-ratio gate and the
+// baseline comparisons. A rollback dominated by a declared dependency
+// that stable is failing on too is downgraded or suppressed.
+func (e *Engine) Evaluate(events []Telemetry) Verdict {
+	v := e.evaluate(events)
+	if v.Decision != Rollback {
+		return v
+	}
+
+	dep, why := e.sharedDependency(events)
+	if dep == "" {
+		return v
+	}
+
+	if e.Policy.DependencyFailures.Action == DependencySuppress {
+		rejudged := e.evaluate(withoutDependencyErrors(events, dep))
+		if rejudged.Decision == Rollback {
+			// still failing on its own errors
+			return v
+		}
+		rejudged.Reason = fmt.Sprintf("rollback suppressed, %s; %s", why, rejudged.Reason)
+		rejudged.SuppressedBy = dep
+		return rejudged
+	}
+
+	v.Decision = Pause
+	v.Reason = fmt.Sprintf("rollback downgraded, %s; %s", why, v.Reason)
+	v.SuppressedBy = dep
+	return v
+}
+
+func (e *Engine) evaluate(events []Telemetry) Verdict {
+	m := e.metrics(events)
+	p := e.Policy
+	mode := p.Analysis.Mode
+
+	var (
+		probability float64
+		why         string
+		concluded   bool
+	)
+
+	switch mode {
+	case ModeBayesian:
+		probability, why = e.bayesian(events)
+		if probability >= p.Analysis.Bayesian.RollbackProbability {
+			return Verdict{Decision: Rollback, Reason: why, Metrics: m, Probability: probability}
+		}
+
+	case ModeSPRT:
+		var d DecisionType
+		d, concluded, why = e.sprt(events)
+		if concluded && d == Rollback {
+			return Verdict{Decision: Rollback, Reason: why, Metrics: m}
+		}
+		if !concluded && e.sprtExhausted(events) {
+			v := e.evaluateFixed(events)
+			v.Reason = fmt.Sprintf("inconclusive after %d windows (%s), judged as a fixed window: %s",
+				p.Analysis.SPRT.maxWindows(), why, v.Reason)
+			return v
+		}
+
+	default:
+		if m.ErrorRate > p.Thresholds.ErrorRate {
+			return Verdict{Decision: p.Actions.OnError, Metrics: m, Reason: fmt.Sprintf(
+				"error rate %.3f > %.3f", m.ErrorRate, p.Thresholds.ErrorRate)}
+		}
+	}
+
+	breached, reason, ratios := e.evaluateRatios(events)
+	m.Ratios = ratios
+	if breached != nil {
+		action := breached.Action
+		if action == "" {
+			action = p.Actions.OnError
+		}
+		return Verdict{Decision: action, Reason: reason, Metrics: m, Probability: probability}
+	}
+
+	// Ratio KPIs are judged before latency, so a failing business metric
+	// is not masked by a latency PAUSE.
+	if mode != ModeBayesian && m.AvgLatencyMs > p.Thresholds.LatencyMs {
+		return Verdict{Decision: p.Actions.OnLatency, Metrics: m, Reason: fmt.Sprintf(
+			"avg latency %.0fms > %.0fms", m.AvgLatencyMs, p.Thresholds.LatencyMs)}
+	}
+
+	if reason := e.trafficShortfall(m); reason != "" {
+		action := p.Actions.OnLowTraffic
+		if action == "" {
+			action = Pause
+		}
+		return Verdict{Decision: action, Reason: reason, Metrics: m, Probability: probability}
 	}
 
+	switch mode {
+	case ModeBayesian:
+		if 1-probability < p.Analysis.Bayesian.PromoteProbability {
+			return Verdict{Decision: Pause, Reason: "inconclusive: " + why, Metrics: m, Probability: probability}
+		}
+		return Verdict{Decision: Promote, Reason: "healthy: " + why, Metrics: m, Probability: probability}
+
+	case ModeSPRT:
+		if !concluded {
+			return Verdict{Decision: Pause, Reason: "inconclusive: " + why, Metrics: m, Pending: true}
+		}
+		return Verdict{Decision: Promote, Reason: "healthy: " + why, Metrics: m}
+	}
+
+	return Verdict{Decision: p.Actions.OnSuccess, Metrics: m, Reason: fmt.Sprintf(
+		"healthy: error rate %.3f, avg latency %.0fms, canary rps %.2f",
+		m.ErrorRate, m.AvgLatencyMs, m.CanaryRPS)}
+}
+
+func (e *Engine) metrics(events []Telemetry) Metrics {
+	var m Metrics
 	var errorCount int
 	var totalLatency float64
 
 	for _, ev := range events {
+		if ev.IsStable() {
+			m.StableEvents++
+			continue
+		}
+
+		m.CanaryEvents++
 		if ev.IsError {
 			errorCount++
 		}
 		totalLatency += ev.LatencyMs
 	}
 
-	errorRate := float64(errorCount) / float64(len(events))
-	avgLatency := totalLatency / float64(len(events))
+	if m.CanaryEvents > 0 {
+		m.ErrorRate = float64(errorCount) / float64(m.CanaryEvents)
+		m.AvgLatencyMs = totalLatency / float64(m.CanaryEvents)
+	}
 
-	if errorRate > e.Policy.Thresholds.ErrorRate {
-		return e.Policy.Actions.OnError
+	if len(events) > 0 {
+		seconds := max(float64(eventSpan(events))/1000, 1)
+		m.CanaryRPS = float64(m.CanaryEvents) / seconds
+		m.StableRPS = float64(m.StableEvents) / seconds
 	}
 
-	if avgLatency > e.Policy.Thresholds.LatencyMs {
-		return e.Policy.Actions.OnLatency
+	return m
+}
+
+// eventSpan returns the event time covered by events, in milliseconds.
+// Rates are taken over it rather than the policy window, since a batch
+// may be decided early or carried over several windows.
+func eventSpan(events []Telemetry) int64 {
+	oldest, newest := events[0].Timestamp, events[0].Timestamp
+	for _, ev := range events[1:] {
+		oldest = min(oldest, ev.Timestamp)
+		newest = max(newest, ev.Timestamp)
+	}
+	return newest - oldest
+}
+
+// trafficShortfall explains why the canary is under-receiving traffic, or
+// returns "" when the traffic gates pass.
+func (e *Engine) trafficShortfall(m Metrics) string {
+	t := e.Policy.Traffic
+
+	if t.MinRPS > 0 && m.CanaryRPS < t.MinRPS {
+		return fmt.Sprintf("canary rps %.2f below minimum %.2f", m.CanaryRPS, t.MinRPS)
+	}
+
+	if t.ExpectedCanaryRatio > 0 && m.StableRPS > 0 {
+		ratio := m.CanaryRPS / m.StableRPS
+		floor := t.ExpectedCanaryRatio * (1 - t.RatioTolerance)
+		if ratio < floor {
+			return fmt.Sprintf("canary/stable traffic ratio %.4f below expected %.4f (tolerance %.0f%%)",
+				ratio, t.ExpectedCanaryRatio, t.RatioTolerance*100)
+		}
 	}
 
-	return e.Policy.Actions.OnSuccess
+	return ""
 }
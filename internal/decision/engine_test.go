@@ -1,26 +1,39 @@
 package decision
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
 
+func testPolicy() *Policy {
+	p := &Policy{Service: "checkout-service", WindowSeconds: 30}
+	p.Thresholds.ErrorRate = 0.05
+	p.Thresholds.LatencyMs = 500
+	p.Actions.OnError = Rollback
+	p.Actions.OnLatency = Pause
+	p.Actions.OnSuccess = Promote
+	return p
+}
+
 func TestRollbackOnHighErrorRate(t *testing.T) {
-	engine := NewEngine()
+	engine := NewEngine(testPolicy())
 
 	events := make([]Telemetry, 0)
 
-	// 40% error rate
+	// 50% error rate
 	for i := 0; i < 100; i++ {
 		events = append(events, Telemetry{
 			ServiceID: "checkout-service",
 			LatencyMs: 120,
 			IsError:   i%2 == 0,
-			Timestamp: time.Now(),
+			Timestamp: time.Now().UnixMilli(),
 		})
 	}
 
-	result := engine.Evaluate(events)
+	result := engine.Evaluate(events).Decision
 
 	if result != Rollback {
 		t.Fatalf("expected ROLLBACK, got %s", result)
@@ -28,7 +41,7 @@ func TestRollbackOnHighErrorRate(t *testing.T) {
 }
 
 func TestPauseOnHighLatency(t *testing.T) {
-	engine := NewEngine()
+	engine := NewEngine(testPolicy())
 
 	events := make([]Telemetry, 0)
 
@@ -38,11 +51,11 @@ func TestPauseOnHighLatency(t *testing.T) {
 			ServiceID: "checkout-service",
 			LatencyMs: 1200,
 			IsError:   false,
-			Timestamp: time.Now(),
+			Timestamp: time.Now().UnixMilli(),
 		})
 	}
 
-	result := engine.Evaluate(events)
+	result := engine.Evaluate(events).Decision
 
 	if result != Pause {
 		t.Fatalf("expected PAUSE, got %s", result)
@@ -50,7 +63,7 @@ func TestPauseOnHighLatency(t *testing.T) {
 }
 
 func TestPromoteOnHealthyMetrics(t *testing.T) {
-	engine := NewEngine()
+	engine := NewEngine(testPolicy())
 
 	events := make([]Telemetry, 0)
 
@@ -59,13 +72,236 @@ func TestPromoteOnHealthyMetrics(t *testing.T) {
 			ServiceID: "checkout-service",
 			LatencyMs: 150,
 			IsError:   false,
-			Timestamp: time.Now(),
+			Timestamp: time.Now().UnixMilli(),
 		})
 	}
 
-	result := engine.Evaluate(events)
+	result := engine.Evaluate(events).Decision
 
 	if result != Promote {
 		t.Fatalf("expected PROMOTE, got %s", result)
 	}
 }
+
+func TestLoadPoliciesKeyedByService(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("checkout.yaml", "service: checkout-service\nwindow_seconds: 30\n")
+	write("search.yml", "service: search-service\nwindow_seconds: 10\n")
+	write("README.md", "not a policy")
+
+	policies, err := LoadPolicies(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 policies, got %d", len(policies))
+	}
+	if policies["default/search-service"].WindowSeconds != 10 {
+		t.Fatalf("expected 10s window for search-service, got %d", policies["default/search-service"].WindowSeconds)
+	}
+
+	write("checkout-copy.yaml", "service: checkout-service\nwindow_seconds: 60\n")
+	if _, err := LoadPolicies(dir); err == nil {
+		t.Fatal("expected duplicate service error")
+	}
+}
+
+func TestPauseWhenCanaryUnderReceivesTraffic(t *testing.T) {
+	policy := testPolicy()
+	policy.WindowSeconds = 10
+	policy.Traffic.MinRPS = 0.5
+	policy.Traffic.ExpectedCanaryRatio = 0.1
+	policy.Traffic.RatioTolerance = 0.5
+	engine := NewEngine(policy)
+
+	// 10 canary + 1000 stable in 10s: 1 rps clears the minimum, but the
+	// 1% ratio is far below the 10% expected.
+	events := make([]Telemetry, 0)
+	for i := 0; i < 1010; i++ {
+		track := TrackStable
+		if i < 10 {
+			track = TrackCanary
+		}
+		events = append(events, Telemetry{
+			ServiceID: "checkout-service",
+			LatencyMs: 100,
+			Track:     track,
+			Timestamp: int64(i) * 10_000 / 1009,
+		})
+	}
+
+	v := engine.Evaluate(events)
+	if v.Decision != Pause {
+		t.Fatalf("expected PAUSE, got %s (%s)", v.Decision, v.Reason)
+	}
+	if v.Metrics.CanaryRPS != 1 || v.Metrics.StableRPS != 100 {
+		t.Fatalf("unexpected rps: %+v", v.Metrics)
+	}
+
+	// No traffic at all trips the minimum-rps gate.
+	v = engine.Evaluate(nil)
+	if v.Decision != Pause {
+		t.Fatalf("expected PAUSE on empty window, got %s (%s)", v.Decision, v.Reason)
+	}
+}
+
+func TestTrafficRatesAreTakenOverTheBatchSpan(t *testing.T) {
+	policy := testPolicy()
+	policy.Traffic.MinRPS = 1
+	engine := NewEngine(policy)
+
+	canary := func(n int, spanMs int64) []Telemetry {
+		events := make([]Telemetry, n)
+		for i := range events {
+			events[i] = Telemetry{ServiceID: "checkout-service", LatencyMs: 100, Timestamp: int64(i) * spanMs / int64(n-1)}
+		}
+		return events
+	}
+
+	// 20 events decided 2s into a 30s window are 10 rps, not 0.67.
+	if v := engine.Evaluate(canary(20, 2_000)); v.Decision != Promote || v.Metrics.CanaryRPS != 10 {
+		t.Fatalf("expected PROMOTE at 10 rps for an early batch, got %s at %.2f rps", v.Decision, v.Metrics.CanaryRPS)
+	}
+
+	// 60 events carried over five windows are 0.4 rps, not 2.
+	if v := engine.Evaluate(canary(60, 150_000)); v.Decision != Pause || v.Metrics.CanaryRPS != 0.4 {
+		t.Fatalf("expected PAUSE at 0.4 rps for a carried batch, got %s at %.2f rps", v.Decision, v.Metrics.CanaryRPS)
+	}
+
+	// The span is floored at a second.
+	if v := engine.Evaluate(canary(2, 100)); v.Metrics.CanaryRPS != 2 {
+		t.Fatalf("expected 2 rps over a floored 1s span, got %.2f", v.Metrics.CanaryRPS)
+	}
+}
+
+func TestRatioMetricBaselineRelative(t *testing.T) {
+	maxDecrease := 0.02
+	policy := testPolicy()
+	policy.Metrics = []RatioMetric{{
+		Name:           "payment_success_rate",
+		Numerator:      "payment_success",
+		Denominator:    "payment_attempt",
+		MinDenominator: 50,
+		MaxDecrease:    &maxDecrease,
+	}}
+	engine := NewEngine(policy)
+
+	payments := func(track string, attempts, successes int) []Telemetry {
+		var events []Telemetry
+		for i := 0; i < attempts; i++ {
+			c := map[string]float64{"payment_attempt": 1}
+			if i < successes {
+				c["payment_success"] = 1
+			}
+			events = append(events, Telemetry{ServiceID: "checkout-service", LatencyMs: 100, Track: track, Counters: c})
+		}
+		return events
+	}
+
+	// canary 90% vs stable 98%: breach, defaults to on_error
+	events := append(payments(TrackCanary, 100, 90), payments(TrackStable, 100, 98)...)
+	v := engine.Evaluate(events)
+	if v.Decision != Rollback {
+		t.Fatalf("expected ROLLBACK, got %s (%s)", v.Decision, v.Reason)
+	}
+	if got := v.Metrics.Ratios["payment_success_rate"]; got.Canary != 0.9 || got.Baseline != 0.98 {
+		t.Fatalf("unexpected ratio values: %+v", got)
+	}
+
+	// canary 97% vs stable 98% is within 2% of baseline
+	events = append(payments(TrackCanary, 100, 97), payments(TrackStable, 100, 98)...)
+	if v := engine.Evaluate(events); v.Decision != Promote {
+		t.Fatalf("expected PROMOTE, got %s (%s)", v.Decision, v.Reason)
+	}
+
+	// too few attempts to judge
+	events = append(payments(TrackCanary, 10, 0), payments(TrackStable, 100, 98)...)
+	if v := engine.Evaluate(events); v.Decision != Promote {
+		t.Fatalf("expected PROMOTE below min_denominator, got %s (%s)", v.Decision, v.Reason)
+	}
+}
+
+func TestRatioMetricAbsoluteMinWithCustomAction(t *testing.T) {
+	floor := 0.95
+	policy := testPolicy()
+	policy.Metrics = []RatioMetric{{
+		Name:        "conversion",
+		Numerator:   "order_placed",
+		Denominator: "checkout_started",
+		Min:         &floor,
+		Action:      Pause,
+	}}
+	engine := NewEngine(policy)
+
+	events := []Telemetry{
+		{ServiceID: "checkout-service", LatencyMs: 100, Counters: map[string]float64{"checkout_started": 10, "order_placed": 9}},
+	}
+	if v := engine.Evaluate(events); v.Decision != Pause {
+		t.Fatalf("expected PAUSE, got %s (%s)", v.Decision, v.Reason)
+	}
+}
+
+func TestRatioMetricTakesPrecedenceOverLatency(t *testing.T) {
+	floor := 0.95
+	policy := testPolicy()
+	policy.Metrics = []RatioMetric{{
+		Name:        "conversion",
+		Numerator:   "order_placed",
+		Denominator: "checkout_started",
+		Min:         &floor,
+	}}
+	engine := NewEngine(policy)
+
+	events := []Telemetry{
+		{ServiceID: "checkout-service", LatencyMs: 900, Counters: map[string]float64{"checkout_started": 10, "order_placed": 9}},
+	}
+	v := engine.Evaluate(events)
+	if v.Decision != Rollback || !strings.Contains(v.Reason, "conversion") {
+		t.Fatalf("expected the ratio breach to win over latency, got %s (%s)", v.Decision, v.Reason)
+	}
+}
+
+func TestSharedDependencyFailureDowngradesRollback(t *testing.T) {
+	policy := testPolicy()
+	policy.Dependencies = []string{"payments-gateway"}
+	engine := NewEngine(policy)
+
+	window := func(canaryDep, stableDep string) []Telemetry {
+		var events []Telemetry
+		for i := 0; i < 100; i++ {
+			ev := Telemetry{ServiceID: "checkout-service", LatencyMs: 100, Track: TrackCanary}
+			if i < 10 {
+				ev.IsError, ev.Dependency = true, canaryDep
+			}
+			events = append(events, ev)
+
+			st := Telemetry{ServiceID: "checkout-service", LatencyMs: 100, Track: TrackStable}
+			if i < 8 {
+				st.IsError, st.Dependency = true, stableDep
+			}
+			events = append(events, st)
+		}
+		return events
+	}
+
+	v := engine.Evaluate(window("payments-gateway", "payments-gateway"))
+	if v.Decision != Pause || v.SuppressedBy != "payments-gateway" {
+		t.Fatalf("expected PAUSE blamed on payments-gateway, got %s (%s)", v.Decision, v.Reason)
+	}
+
+	// stable is healthy on that dependency: the canary owns its errors
+	if v := engine.Evaluate(window("payments-gateway", "")); v.Decision != Rollback {
+		t.Fatalf("expected ROLLBACK, got %s (%s)", v.Decision, v.Reason)
+	}
+
+	policy.DependencyFailures.Action = DependencySuppress
+	v = engine.Evaluate(window("payments-gateway", "payments-gateway"))
+	if v.Decision != Promote || v.SuppressedBy != "payments-gateway" {
+		t.Fatalf("expected suppressed rollback to PROMOTE, got %s (%s)", v.Decision, v.Reason)
+	}
+}
@@ -1,24 +1,62 @@
 package decision
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
 )
 
 type Policy struct {
+	// Tenant namespaces Service; empty is storage.DefaultTenant.
+	Tenant        string `yaml:"tenant,omitempty"`
 	Service       string `yaml:"service"`
 	WindowSeconds int    `yaml:"window_seconds"`
 
+	// Version is assigned by the policy store, not read from YAML.
+	Version int64 `yaml:"-"`
+
 	Thresholds struct {
 		ErrorRate float64 `yaml:"error_rate"`
 		LatencyMs float64 `yaml:"latency_ms"`
 	} `yaml:"thresholds"`
 
+	// Analysis selects how canary health is judged; the default
+	// "threshold" mode compares window averages against Thresholds.
+	Analysis struct {
+		Mode     string         `yaml:"mode,omitempty"`
+		Bayesian BayesianConfig `yaml:"bayesian,omitempty"`
+		SPRT     SPRTConfig     `yaml:"sprt,omitempty"`
+	} `yaml:"analysis,omitempty"`
+
+	Metrics []RatioMetric `yaml:"metrics,omitempty"`
+
+	// Dependencies are the shared downstreams telemetry may blame for a
+	// failure (failing_dependency).
+	Dependencies       []string           `yaml:"dependencies,omitempty"`
+	DependencyFailures DependencyFailures `yaml:"dependency_failures,omitempty"`
+
+	// Steps are the canary traffic weights a rollout walks through, one
+	// healthy window at a time. No steps means a single PROMOTE finishes
+	// the rollout.
+	Steps []Step `yaml:"steps,omitempty"`
+
+	// Traffic gates guard against a canary that silently receives too
+	// little traffic to be judged. Zero values disable a gate.
+	Traffic struct {
+		MinRPS              float64 `yaml:"min_rps,omitempty"`
+		ExpectedCanaryRatio float64 `yaml:"expected_canary_ratio,omitempty"`
+		RatioTolerance      float64 `yaml:"ratio_tolerance,omitempty"`
+	} `yaml:"traffic,omitempty"`
+
 	Actions struct {
-		OnError   DecisionType `yaml:"on_error"`
-		OnLatency DecisionType `yaml:"on_latency"`
-		OnSuccess DecisionType `yaml:"on_success"`
+		OnError      DecisionType `yaml:"on_error"`
+		OnLatency    DecisionType `yaml:"on_latency"`
+		OnSuccess    DecisionType `yaml:"on_success"`
+		OnLowTraffic DecisionType `yaml:"on_low_traffic,omitempty"`
 	} `yaml:"actions"`
 }
 
@@ -28,6 +66,10 @@ func LoadPolicy(path string) (*Policy, error) {
 		return nil, err
 	}
 
+	return ParsePolicy(data)
+}
+
+func ParsePolicy(data []byte) (*Policy, error) {
 	var p Policy
 	if err := yaml.Unmarshal(data, &p); err != nil {
 		return nil, err
@@ -35,3 +77,120 @@ func LoadPolicy(path string) (*Policy, error) {
 
 	return &p, nil
 }
+
+// Key returns the store key of the policy's service.
+func (p *Policy) Key() string {
+	return storage.Key(p.Tenant, p.Service)
+}
+
+func (p *Policy) Marshal() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// LoadPolicies loads every *.yaml / *.yml file in dir, keyed by
+// storage.Key.
+func LoadPolicies(dir string) (map[string]*Policy, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]*Policy)
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		p, err := LoadPolicy(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		if _, dup := policies[p.Key()]; dup {
+			return nil, fmt.Errorf("%s: duplicate policy for service %q", e.Name(), p.Key())
+		}
+
+		policies[p.Key()] = p
+	}
+
+	return policies, nil
+}
+
+const (
+	ModeThreshold = "threshold"
+	ModeBayesian  = "bayesian"
+	ModeSPRT      = "sprt"
+)
+
+type Step struct {
+	Weight int `yaml:"weight"`
+	// Approval, if set, holds the rollout at this step until enough
+	// humans sign off on going past it.
+	Approval *Approval `yaml:"approval,omitempty"`
+}
+
+type Approval struct {
+	RequiredApprovers int `yaml:"required_approvers"`
+}
+
+func (p *Policy) Validate() error {
+	if p.Service == "" {
+		return fmt.Errorf("policy has no service")
+	}
+	if err := storage.ValidateTenant(p.Tenant); err != nil {
+		return fmt.Errorf("service %q: %w", p.Service, err)
+	}
+	if p.WindowSeconds <= 0 {
+		return fmt.Errorf("service %q: window_seconds must be positive", p.Service)
+	}
+	if p.Traffic.MinRPS < 0 || p.Traffic.ExpectedCanaryRatio < 0 {
+		return fmt.Errorf("service %q: traffic gates must not be negative", p.Service)
+	}
+	if p.Traffic.RatioTolerance < 0 || p.Traffic.RatioTolerance >= 1 {
+		return fmt.Errorf("service %q: traffic.ratio_tolerance must be in [0, 1)", p.Service)
+	}
+
+	switch p.Analysis.Mode {
+	case "", ModeThreshold:
+	case ModeBayesian:
+		if err := p.Analysis.Bayesian.validate(); err != nil {
+			return fmt.Errorf("service %q: %w", p.Service, err)
+		}
+	case ModeSPRT:
+		if err := p.Analysis.SPRT.validate(); err != nil {
+			return fmt.Errorf("service %q: %w", p.Service, err)
+		}
+	default:
+		return fmt.Errorf("service %q: unknown analysis mode %q", p.Service, p.Analysis.Mode)
+	}
+
+	if err := p.DependencyFailures.validate(); err != nil {
+		return fmt.Errorf("service %q: %w", p.Service, err)
+	}
+
+	names := make(map[string]bool, len(p.Metrics))
+	for _, m := range p.Metrics {
+		if err := m.validate(); err != nil {
+			return fmt.Errorf("service %q: %w", p.Service, err)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("service %q: duplicate metric %q", p.Service, m.Name)
+		}
+		names[m.Name] = true
+	}
+
+	prev := 0
+	for i, step := range p.Steps {
+		if step.Weight <= prev || step.Weight > 100 {
+			return fmt.Errorf("service %q: step %d weight must increase and be at most 100", p.Service, i)
+		}
+		if step.Approval != nil && step.Approval.RequiredApprovers < 1 {
+			return fmt.Errorf("service %q: step %d approval needs at least one approver", p.Service, i)
+		}
+		prev = step.Weight
+	}
+	return nil
+}
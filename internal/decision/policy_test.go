@@ -0,0 +1,60 @@
+package decision
+
+import "testing"
+
+func TestPolicyMarshalRoundTrip(t *testing.T) {
+	in := testPolicy()
+	in.Version = 7
+
+	spec, err := in.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ParsePolicy(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Service != in.Service || out.Thresholds != in.Thresholds || out.Actions != in.Actions {
+		t.Fatalf("round trip mismatch: %+v vs %+v", out, in)
+	}
+	if out.Version != 0 {
+		t.Fatalf("version must not be serialized, got %d", out.Version)
+	}
+}
+
+func TestWindowIDIsAlignedToPolicyWindow(t *testing.T) {
+	p := &Policy{Service: "checkout-service", WindowSeconds: 30}
+
+	a := p.WindowID(1_718_000_010_000)
+	b := p.WindowID(1_718_000_039_999)
+	c := p.WindowID(1_718_000_040_000)
+	if a != b {
+		t.Fatalf("events in one window got different IDs: %s vs %s", a, b)
+	}
+	if a == c {
+		t.Fatalf("events in different windows share ID %s", a)
+	}
+	if a != "checkout-service/30s/1718000010000" {
+		t.Fatalf("unexpected window ID %s", a)
+	}
+
+	p.WindowSeconds = 60
+	if p.WindowID(1_718_000_039_999) == b {
+		t.Fatal("window ID must change with the window length")
+	}
+}
+
+func TestShippedPoliciesLoad(t *testing.T) {
+	policies, err := LoadPolicies("../../deploy/policies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkout := policies["default/checkout-service"]
+	if checkout == nil {
+		t.Fatalf("expected the checkout-service policy, got %d policies", len(policies))
+	}
+	if checkout.Actions.OnLowTraffic != Pause || len(checkout.Steps) != 4 || len(checkout.Dependencies) != 2 {
+		t.Fatalf("unexpected checkout-service policy: %+v", checkout)
+	}
+}
@@ -0,0 +1,121 @@
+package decision
+
+import "fmt"
+
+// RatioMetric is a business KPI computed from telemetry counters, e.g.
+// payment_success / payment_attempt.
+type RatioMetric struct {
+	Name        string `yaml:"name"`
+	Numerator   string `yaml:"numerator"`
+	Denominator string `yaml:"denominator"`
+
+	// MinDenominator is the sample size below which the metric is not
+	// judged (default 1).
+	MinDenominator float64 `yaml:"min_denominator,omitempty"`
+
+	// Absolute bounds on the canary ratio.
+	Min *float64 `yaml:"min,omitempty"`
+	Max *float64 `yaml:"max,omitempty"`
+
+	// Bounds relative to the stable baseline ratio, as fractions of it:
+	// max_decrease 0.02 fails a canary more than 2% below baseline.
+	MaxDecrease *float64 `yaml:"max_decrease,omitempty"`
+	MaxIncrease *float64 `yaml:"max_increase,omitempty"`
+
+	// Action defaults to the policy's on_error action.
+	Action DecisionType `yaml:"action,omitempty"`
+}
+
+type RatioValue struct {
+	Canary   float64
+	Baseline float64
+	// HasBaseline is false when stable traffic did not reach
+	// min_denominator.
+	HasBaseline bool
+}
+
+func (r RatioMetric) validate() error {
+	if r.Name == "" || r.Numerator == "" || r.Denominator == "" {
+		return fmt.Errorf("ratio metric needs name, numerator and denominator")
+	}
+	if r.Min == nil && r.Max == nil && r.MaxDecrease == nil && r.MaxIncrease == nil {
+		return fmt.Errorf("ratio metric %q has no threshold", r.Name)
+	}
+	return nil
+}
+
+func (r RatioMetric) minDenominator() float64 {
+	if r.MinDenominator > 0 {
+		return r.MinDenominator
+	}
+	return 1
+}
+
+// ratio sums the metric's counters over events of one track. ok is false
+// when there are too few denominator events to judge.
+func (r RatioMetric) ratio(events []Telemetry, stable bool) (float64, bool) {
+	var num, den float64
+	for _, ev := range events {
+		if ev.IsStable() != stable {
+			continue
+		}
+		num += ev.Counters[r.Numerator]
+		den += ev.Counters[r.Denominator]
+	}
+
+	if den < r.minDenominator() {
+		return 0, false
+	}
+	return num / den, true
+}
+
+// evaluateRatios returns the first breached KPI as a reason, plus the
+// computed values of every metric that had enough samples.
+func (e *Engine) evaluateRatios(events []Telemetry) (*RatioMetric, string, map[string]RatioValue) {
+	var (
+		breached *RatioMetric
+		reason   string
+		values   = make(map[string]RatioValue)
+	)
+
+	for i := range e.Policy.Metrics {
+		r := &e.Policy.Metrics[i]
+
+		canary, ok := r.ratio(events, false)
+		if !ok {
+			continue
+		}
+		baseline, hasBaseline := r.ratio(events, true)
+		values[r.Name] = RatioValue{Canary: canary, Baseline: baseline, HasBaseline: hasBaseline}
+
+		if breached != nil {
+			continue
+		}
+		if why := r.breach(canary, baseline, hasBaseline); why != "" {
+			breached, reason = r, why
+		}
+	}
+
+	return breached, reason, values
+}
+
+func (r RatioMetric) breach(canary, baseline float64, hasBaseline bool) string {
+	if r.Min != nil && canary < *r.Min {
+		return fmt.Sprintf("%s %.4f < min %.4f", r.Name, canary, *r.Min)
+	}
+	if r.Max != nil && canary > *r.Max {
+		return fmt.Sprintf("%s %.4f > max %.4f", r.Name, canary, *r.Max)
+	}
+	if !hasBaseline {
+		return ""
+	}
+	if r.MaxDecrease != nil && canary < baseline*(1-*r.MaxDecrease) {
+		return fmt.Sprintf("%s %.4f more than %.1f%% below baseline %.4f",
+			r.Name, canary, *r.MaxDecrease*100, baseline)
+	}
+	if r.MaxIncrease != nil && canary > baseline*(1+*r.MaxIncrease) {
+		return fmt.Sprintf("%s %.4f more than %.1f%% above baseline %.4f",
+			r.Name, canary, *r.MaxIncrease*100, baseline)
+	}
+	return ""
+}
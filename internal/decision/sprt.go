@@ -0,0 +1,129 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+)
+
+// SPRTConfig tunes the sequential probability ratio test on the canary
+// error rate: H0 "error rate is P0" against H1 "error rate is P1".
+type SPRTConfig struct {
+	P0 float64 `yaml:"p0"`
+	P1 float64 `yaml:"p1"`
+	// Alpha is the tolerated false-rollback rate, Beta the tolerated
+	// false-promote rate.
+	Alpha float64 `yaml:"alpha"`
+	Beta  float64 `yaml:"beta"`
+	// MaxWindows bounds how many window lengths of event time an
+	// inconclusive test keeps accumulating; the evidence is then judged
+	// like a fixed window. 0 means DefaultSPRTMaxWindows.
+	MaxWindows int `yaml:"max_windows,omitempty"`
+}
+
+const DefaultSPRTMaxWindows = 5
+
+func (c SPRTConfig) maxWindows() int {
+	if c.MaxWindows == 0 {
+		return DefaultSPRTMaxWindows
+	}
+	return c.MaxWindows
+}
+
+func (c SPRTConfig) validate() error {
+	if c.P0 <= 0 || c.P1 >= 1 || c.P0 >= c.P1 {
+		return fmt.Errorf("sprt needs 0 < p0 < p1 < 1")
+	}
+	if c.Alpha <= 0 || c.Alpha >= 1 || c.Beta <= 0 || c.Beta >= 1 {
+		return fmt.Errorf("sprt alpha and beta must be in (0, 1)")
+	}
+	if c.MaxWindows < 0 {
+		return fmt.Errorf("sprt max_windows must not be negative")
+	}
+	return nil
+}
+
+// bounds returns the log-likelihood ratios at which H1 (rollback) and
+// H0 (promote) are accepted.
+func (c SPRTConfig) bounds() (upper, lower float64) {
+	return math.Log((1 - c.Beta) / c.Alpha), math.Log(c.Beta / (1 - c.Alpha))
+}
+
+// SPRT accumulates evidence one event at a time so a decision can fire as
+// soon as a bound is crossed rather than at the end of a window.
+type SPRT struct {
+	cfg          SPRTConfig
+	llr          float64
+	upper, lower float64
+	n            int
+}
+
+func NewSPRT(cfg SPRTConfig) *SPRT {
+	s := &SPRT{cfg: cfg}
+	s.upper, s.lower = cfg.bounds()
+	return s
+}
+
+// Observe adds a canary event and reports the decision once the
+// evidence is conclusive. Stable events are ignored.
+func (s *SPRT) Observe(ev Telemetry) (DecisionType, bool) {
+	if ev.IsStable() {
+		return "", false
+	}
+
+	s.n++
+	if ev.IsError {
+		s.llr += math.Log(s.cfg.P1 / s.cfg.P0)
+	} else {
+		s.llr += math.Log((1 - s.cfg.P1) / (1 - s.cfg.P0))
+	}
+
+	switch {
+	case s.llr >= s.upper:
+		return Rollback, true
+	case s.llr <= s.lower:
+		return Promote, true
+	}
+	return "", false
+}
+
+func (s *SPRT) Reset() {
+	s.llr = 0
+	s.n = 0
+}
+
+func (s *SPRT) String() string {
+	return fmt.Sprintf("sprt llr %.2f over %d events (bounds %.2f / %.2f)", s.llr, s.n, s.lower, s.upper)
+}
+
+// sprt runs the test over a whole window's events.
+func (e *Engine) sprt(events []Telemetry) (DecisionType, bool, string) {
+	s := NewSPRT(e.Policy.Analysis.SPRT)
+	for _, ev := range events {
+		if d, done := s.Observe(ev); done {
+			return d, true, s.String()
+		}
+	}
+	return "", false, s.String()
+}
+
+// sprtExhausted reports whether events span the longest an inconclusive
+// test may run.
+func (e *Engine) sprtExhausted(events []Telemetry) bool {
+	if len(events) == 0 {
+		return false
+	}
+	oldest, newest := events[0].Timestamp, events[0].Timestamp
+	for _, ev := range events[1:] {
+		oldest, newest = min(oldest, ev.Timestamp), max(newest, ev.Timestamp)
+	}
+	limit := int64(e.Policy.Analysis.SPRT.maxWindows()) * int64(e.Policy.WindowSeconds) * 1000
+	return newest-oldest >= limit
+}
+
+// evaluateFixed judges events by the fixed thresholds, for a sequential
+// test that stayed inconclusive for too long.
+func (e *Engine) evaluateFixed(events []Telemetry) Verdict {
+	p := *e.Policy
+	p.Analysis.Mode = ModeThreshold
+	return NewEngine(&p).evaluate(events)
+}
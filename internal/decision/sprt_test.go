@@ -0,0 +1,89 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+)
+
+func sprtPolicy() *Policy {
+	p := testPolicy()
+	p.Analysis.Mode = ModeSPRT
+	p.Analysis.SPRT = SPRTConfig{P0: 0.01, P1: 0.05, Alpha: 0.05, Beta: 0.1}
+	return p
+}
+
+func TestSPRTFiresEarlyOnCatastrophicCanary(t *testing.T) {
+	s := NewSPRT(sprtPolicy().Analysis.SPRT)
+
+	for i := 1; i <= 100; i++ {
+		d, done := s.Observe(Telemetry{ServiceID: "checkout-service", IsError: true})
+		if done {
+			if d != Rollback {
+				t.Fatalf("expected ROLLBACK, got %s", d)
+			}
+			if i > 3 {
+				t.Fatalf("expected rollback within 3 errors, took %d", i)
+			}
+			return
+		}
+	}
+	t.Fatal("sprt never concluded")
+}
+
+func TestSPRTWindowVerdicts(t *testing.T) {
+	engine := NewEngine(sprtPolicy())
+
+	clean := make([]Telemetry, 0)
+	for i := 0; i < 20; i++ {
+		clean = append(clean, Telemetry{ServiceID: "checkout-service", LatencyMs: 100})
+	}
+	v := engine.Evaluate(clean)
+	if !v.Pending || v.Decision != Pause {
+		t.Fatalf("expected pending verdict on 20 clean events, got %s pending=%v (%s)", v.Decision, v.Pending, v.Reason)
+	}
+
+	for i := 0; i < 200; i++ {
+		clean = append(clean, Telemetry{ServiceID: "checkout-service", LatencyMs: 100})
+	}
+	v = engine.Evaluate(clean)
+	if v.Pending || v.Decision != Promote {
+		t.Fatalf("expected PROMOTE on 220 clean events, got %s pending=%v (%s)", v.Decision, v.Pending, v.Reason)
+	}
+
+	// Stable errors are not canary evidence.
+	stable := []Telemetry{{ServiceID: "checkout-service", IsError: true, Track: TrackStable}}
+	if v := engine.Evaluate(stable); v.Decision == Rollback {
+		t.Fatalf("stable errors must not roll back the canary: %s", v.Reason)
+	}
+}
+
+func TestSPRTInconclusiveForMaxWindowsIsJudgedAsFixedWindow(t *testing.T) {
+	policy := sprtPolicy()
+	policy.Analysis.SPRT.MaxWindows = 2
+	engine := NewEngine(policy)
+
+	// One error in 40 keeps the evidence swinging between the bounds
+	// while staying under the fixed 5% error threshold.
+	var events []Telemetry
+	add := func(n int, start int64) {
+		for i := 0; i < n; i++ {
+			events = append(events, Telemetry{
+				ServiceID: "checkout-service",
+				LatencyMs: 100,
+				IsError:   len(events)%40 == 0,
+				Timestamp: start + int64(i)*100,
+			})
+		}
+	}
+
+	add(80, 1_718_000_000_000)
+	if v := engine.Evaluate(events); !v.Pending {
+		t.Fatalf("expected a pending verdict within the limit, got %s (%s)", v.Decision, v.Reason)
+	}
+
+	add(80, 1_718_000_060_000)
+	v := engine.Evaluate(events)
+	if v.Pending || v.Decision != Promote || !strings.Contains(v.Reason, "fixed window") {
+		t.Fatalf("expected a fixed-window PROMOTE after 2 windows, got %s pending=%v (%s)", v.Decision, v.Pending, v.Reason)
+	}
+}
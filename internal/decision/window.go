@@ -0,0 +1,40 @@
+package decision
+
+import "fmt"
+
+// WindowStart returns the start, in unix ms, of the policy window that
+// contains ts. Windows are aligned to multiples of the window length
+// since the Unix epoch, so every engine and every replay agrees on them
+// regardless of time zone or when it started.
+func (p *Policy) WindowStart(ts int64) int64 {
+	windowMs := int64(p.WindowSeconds) * 1000
+	start := ts - ts%windowMs
+	if ts < 0 && ts%windowMs != 0 {
+		start -= windowMs
+	}
+	return start
+}
+
+// WindowID names the policy window containing ts, e.g.
+// "checkout-service/30s/1718000010000".
+func (p *Policy) WindowID(ts int64) string {
+	return fmt.Sprintf("%s/%ds/%d", p.Service, p.WindowSeconds, p.WindowStart(ts))
+}
+
+// BatchID names one evaluated batch of events: the policy window of its
+// newest event and the event-time span of the batch, e.g.
+// "checkout-service/30s/1718000010000/1718000012000-1718000039999".
+// A replay of the same batch gets the same ID, while two batches decided
+// within one policy window (an early sequential decision, or sparse
+// traffic spread over several ticks) get different ones. An empty batch
+// has no event time and gets "".
+func (p *Policy) BatchID(events []Telemetry) string {
+	if len(events) == 0 {
+		return ""
+	}
+	oldest, newest := events[0].Timestamp, events[0].Timestamp
+	for _, ev := range events[1:] {
+		oldest, newest = min(oldest, ev.Timestamp), max(newest, ev.Timestamp)
+	}
+	return fmt.Sprintf("%s/%d-%d", p.WindowID(newest), oldest, newest)
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+var errNoRollout = errors.New("no rollout")
+
+func (s *Server) ApproveRollout(
+	ctx context.Context,
+	req *rolloutpb.ApproveRolloutRequest,
+) (*rolloutpb.ApproveRolloutResponse, error) {
+	if req.ServiceId == "" || req.Approver == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id and approver are required")
+	}
+
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.loadPolicy(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		step      int
+		required  int
+		prevState storage.RolloutState
+	)
+	st, err := storage.Update(ctx, s.store, key, func(cur *storage.State) (*storage.State, error) {
+		if cur == nil {
+			return nil, errNoRollout
+		}
+		step, prevState = cur.Step, cur.State
+		var err error
+		if required, err = s.machine.Approve(cur, policy, req.Approver, req.Comment); err != nil {
+			return nil, err
+		}
+		cur.History = []storage.HistoryEntry{{
+			RolloutID:     cur.RolloutID,
+			Tenant:        policy.Tenant,
+			ServiceID:     req.ServiceId,
+			Kind:          storage.HistoryApproval,
+			Actor:         req.Approver,
+			From:          prevState,
+			To:            cur.State,
+			Comment:       req.Comment,
+			PolicyVersion: policy.Version,
+			TrafficWeight: cur.TrafficWeight,
+		}}
+		return cur, nil
+	})
+	switch {
+	case errors.Is(err, errNoRollout):
+		return nil, status.Errorf(codes.NotFound, "no rollout for service %q", key)
+	case errors.Is(err, rollout.ErrNotAwaitingApproval):
+		return nil, status.Errorf(codes.FailedPrecondition, "%v (state %s)", err, prevState)
+	case errors.Is(err, rollout.ErrDuplicateApprover):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.As(err, new(*rollout.TransitionError)):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, storage.ErrVersionConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &rolloutpb.ApproveRolloutResponse{
+		State:             string(st.State),
+		Approvals:         int32(rollout.ApprovalsAt(st, step)),
+		RequiredApprovers: int32(required),
+		TrafficWeight:     int32(st.TrafficWeight),
+	}, nil
+}
+
+func (s *Server) loadPolicy(ctx context.Context, key string) (*decision.Policy, error) {
+	rec, err := s.store.GetPolicy(ctx, key)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if rec == nil {
+		return nil, status.Errorf(codes.NotFound, "no policy for service %q", key)
+	}
+
+	policy, err := decision.ParsePolicy([]byte(rec.Spec))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "stored policy is invalid: %v", err)
+	}
+	policy.Version = rec.Version
+	return policy, nil
+}
@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+func TestApproveRolloutWritesItsHistoryWithTheState(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestServer(t)
+	putTestPolicy(t, store)
+	putTestRollout(t, store, storage.AwaitingApproval, 0)
+	s := NewServer(noAppendStore{store}, rollout.NewMachine())
+
+	resp, err := s.ApproveRollout(ctx, &rolloutpb.ApproveRolloutRequest{
+		ServiceId: "checkout-service",
+		Approver:  "bob",
+		Comment:   "dashboards look fine",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.State != string(storage.Canary) || resp.Approvals != 1 {
+		t.Fatalf("expected the gate to open, got %+v", resp)
+	}
+
+	entries, _, err := store.History(ctx, testKey, 0, 0, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Kind != storage.HistoryApproval || entries[0].Actor != "bob" ||
+		entries[0].From != storage.AwaitingApproval || entries[0].To != storage.Canary {
+		t.Fatalf("expected the APPROVAL entry, got %+v", entries)
+	}
+}
@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const (
+	defaultHistoryPageSize = 100
+	maxHistoryPageSize     = 1000
+	backfillPageSize       = 500
+)
+
+func (s *Server) GetRolloutHistory(
+	ctx context.Context,
+	req *rolloutpb.GetRolloutHistoryRequest,
+) (*rolloutpb.GetRolloutHistoryResponse, error) {
+	if req.ServiceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id is required")
+	}
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	if req.EndUnixMs > 0 && req.EndUnixMs < req.StartUnixMs {
+		return nil, status.Error(codes.InvalidArgument, "end_unix_ms is before start_unix_ms")
+	}
+
+	size := int64(req.PageSize)
+	switch {
+	case size <= 0:
+		size = defaultHistoryPageSize
+	case size > maxHistoryPageSize:
+		size = maxHistoryPageSize
+	}
+
+	entries, next, err := s.store.History(ctx, key,
+		req.StartUnixMs, req.EndUnixMs, req.PageToken, size)
+	if errors.Is(err, storage.ErrInvalidPageToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &rolloutpb.GetRolloutHistoryResponse{NextPageToken: next}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, toHistoryPB(e))
+	}
+	return resp, nil
+}
+
+func toHistoryPB(e *storage.HistoryEntry) *rolloutpb.HistoryEntry {
+	return &rolloutpb.HistoryEntry{
+		Id:              e.ID,
+		RolloutId:       e.RolloutID,
+		Kind:            string(e.Kind),
+		Actor:           e.Actor,
+		FromState:       string(e.From),
+		ToState:         string(e.To),
+		Verdict:         e.Verdict,
+		Decision:        e.Decision,
+		Reason:          e.Reason,
+		Comment:         e.Comment,
+		PolicyVersion:   e.PolicyVersion,
+		TrafficWeight:   int32(e.TrafficWeight),
+		Metrics:         e.Metrics,
+		TimestampUnixMs: e.Timestamp,
+		DecisionId:      e.DecisionID,
+		Sequence:        e.Sequence,
+	}
+}
+
+// backfill sends the service's recorded decisions numbered from and up,
+// in sequence order, and returns the last sequence it sent (from-1 if
+// none). It reads them page by page from the sequence index, so a client
+// that resumes after a short break costs a short read. Decisions already
+// trimmed by the history retention cannot be replayed, and asking for
+// one is OutOfRange, as is a from beyond the next decision to be made.
+func (s *Server) backfill(
+	stream rolloutpb.RolloutControl_StreamDecisionsServer,
+	key string,
+	from int64,
+) (int64, error) {
+	if from <= 0 {
+		return 0, nil
+	}
+
+	st, err := s.store.Get(stream.Context(), key)
+	if err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	var current int64
+	if st != nil {
+		current = st.Sequence
+	}
+	if from > current+1 {
+		return 0, status.Errorf(codes.OutOfRange,
+			"service %q has only made %d decisions, cannot resume from %d", key, current, from)
+	}
+
+	last := from - 1
+	for {
+		page, err := s.store.DecisionHistory(stream.Context(), key, last+1, backfillPageSize)
+		if errors.Is(err, storage.ErrHistoryTrimmed) {
+			return 0, status.Errorf(codes.OutOfRange,
+				"decision %d of service %q is older than the history retention", last+1, key)
+		}
+		if err != nil {
+			return 0, status.Error(codes.Internal, err.Error())
+		}
+		for _, e := range page {
+			if err := stream.Send(historyEvent(e)); err != nil {
+				return 0, err
+			}
+			last = e.Sequence
+		}
+		if len(page) < backfillPageSize {
+			return last, nil
+		}
+	}
+}
+
+// historyEvent rebuilds the DecisionEvent that announced e.
+func historyEvent(e *storage.HistoryEntry) *rolloutpb.DecisionEvent {
+	tenant, _ := storage.SplitKey(e.Key())
+	ev := &rolloutpb.DecisionEvent{
+		DecisionId:      e.DecisionID,
+		RolloutId:       e.RolloutID,
+		Tenant:          tenant,
+		ServiceId:       e.ServiceID,
+		Decision:        rolloutpb.DecisionType(rolloutpb.DecisionType_value[e.Decision]),
+		Source:          rolloutpb.DecisionSource_AUTOMATED,
+		Reason:          e.Reason,
+		TimestampUnixMs: e.Timestamp,
+		TrafficWeight:   int32(e.TrafficWeight),
+		Sequence:        e.Sequence,
+	}
+	if e.Kind == storage.HistoryManual {
+		ev.Decision = overrideDecision(storage.OverrideAction(e.Decision))
+		ev.Source = rolloutpb.DecisionSource_MANUAL
+		ev.Actor = e.Actor
+	}
+	return ev
+}
@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+var errRolloutMismatch = errors.New("rollout is no longer current")
+
+func (s *Server) PauseRollout(ctx context.Context, req *rolloutpb.OverrideRequest) (*rolloutpb.OverrideResponse, error) {
+	return s.override(ctx, req, storage.OverridePause)
+}
+
+func (s *Server) ResumeRollout(ctx context.Context, req *rolloutpb.OverrideRequest) (*rolloutpb.OverrideResponse, error) {
+	return s.override(ctx, req, storage.OverrideResume)
+}
+
+func (s *Server) AbortRollout(ctx context.Context, req *rolloutpb.OverrideRequest) (*rolloutpb.OverrideResponse, error) {
+	return s.override(ctx, req, storage.OverrideAbort)
+}
+
+func (s *Server) ForcePromote(ctx context.Context, req *rolloutpb.OverrideRequest) (*rolloutpb.OverrideResponse, error) {
+	return s.override(ctx, req, storage.OverridePromote)
+}
+
+// override applies an operator's action through the state machine and
+// queues a MANUAL DecisionEvent in the same write. The outbox relay on
+// the leading engine delivers it to Kafka and the decision streams.
+func (s *Server) override(
+	ctx context.Context,
+	req *rolloutpb.OverrideRequest,
+	action storage.OverrideAction,
+) (*rolloutpb.OverrideResponse, error) {
+	if req.ServiceId == "" || req.Actor == "" || req.Reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id, actor and reason are required")
+	}
+	if req.DurationSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "duration_seconds must not be negative")
+	}
+	if req.DurationSeconds > 0 && action != storage.OverridePause {
+		return nil, status.Error(codes.InvalidArgument, "duration_seconds only applies to PauseRollout")
+	}
+
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.loadPolicy(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	o := storage.Override{
+		Action: action,
+		Actor:  req.Actor,
+		Reason: req.Reason,
+		SetAt:  now.UnixMilli(),
+	}
+	if req.DurationSeconds > 0 {
+		o.ExpiresAt = now.Add(time.Duration(req.DurationSeconds) * time.Second).UnixMilli()
+	}
+
+	var (
+		prevState  storage.RolloutState
+		decisionID string
+	)
+	st, err := storage.Update(ctx, s.store, key, func(cur *storage.State) (*storage.State, error) {
+		if cur == nil {
+			return nil, errNoRollout
+		}
+		if req.RolloutId != "" && cur.RolloutID != req.RolloutId {
+			return nil, errRolloutMismatch
+		}
+		prevState = cur.State
+		if err := s.machine.Override(cur, policy, o); err != nil {
+			return nil, err
+		}
+		// The write produces revision cur.Revision+1, which makes the ID
+		// unique within the rollout.
+		decisionID = fmt.Sprintf("%s/manual/%d", cur.RolloutID, cur.Revision+1)
+		cur.Sequence++
+		cur.Outbox = []storage.OutboxMessage{manualMessage(cur, decisionID, o)}
+		cur.History = []storage.HistoryEntry{{
+			RolloutID:     cur.RolloutID,
+			Tenant:        policy.Tenant,
+			ServiceID:     req.ServiceId,
+			Kind:          storage.HistoryManual,
+			Actor:         req.Actor,
+			From:          prevState,
+			To:            cur.State,
+			Decision:      string(action),
+			Reason:        req.Reason,
+			PolicyVersion: policy.Version,
+			TrafficWeight: cur.TrafficWeight,
+			DecisionID:    decisionID,
+			Sequence:      cur.Sequence,
+		}}
+		return cur, nil
+	})
+	switch {
+	case errors.Is(err, errNoRollout):
+		return nil, status.Errorf(codes.NotFound, "no rollout for service %q", key)
+	case errors.Is(err, errRolloutMismatch):
+		return nil, status.Errorf(codes.FailedPrecondition, "rollout %s is no longer current for service %q", req.RolloutId, key)
+	case errors.Is(err, rollout.ErrNotPaused):
+		return nil, status.Errorf(codes.FailedPrecondition, "%v (state %s)", err, prevState)
+	case errors.As(err, new(*rollout.TransitionError)):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, storage.ErrVersionConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &rolloutpb.OverrideResponse{Rollout: toRolloutPB(st)}, nil
+}
+
+func manualMessage(st *storage.State, id string, o storage.Override) storage.OutboxMessage {
+	tenant, _ := storage.SplitKey(st.Key())
+	bytes, _ := proto.Marshal(&rolloutpb.DecisionEvent{
+		DecisionId:      id,
+		RolloutId:       st.RolloutID,
+		Tenant:          tenant,
+		ServiceId:       st.ServiceID,
+		Decision:        overrideDecision(o.Action),
+		Source:          rolloutpb.DecisionSource_MANUAL,
+		Actor:           o.Actor,
+		Reason:          o.Reason,
+		TimestampUnixMs: o.SetAt,
+		TrafficWeight:   int32(st.TrafficWeight),
+		Sequence:        st.Sequence,
+	})
+	return storage.OutboxMessage{DecisionID: id, Payload: bytes}
+}
+
+func overrideDecision(a storage.OverrideAction) rolloutpb.DecisionType {
+	switch a {
+	case storage.OverridePause:
+		return rolloutpb.DecisionType_PAUSE
+	case storage.OverrideResume:
+		return rolloutpb.DecisionType_RESUME
+	case storage.OverrideAbort:
+		return rolloutpb.DecisionType_ROLLBACK
+	case storage.OverridePromote:
+		return rolloutpb.DecisionType_PROMOTE
+	default:
+		return rolloutpb.DecisionType_DECISION_UNKNOWN
+	}
+}
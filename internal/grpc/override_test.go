@@ -0,0 +1,238 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const testPolicySpec = `
+service: checkout-service
+window_seconds: 30
+thresholds:
+  error_rate: 0.05
+  latency_ms: 500
+steps:
+  - weight: 10
+  - weight: 50
+    approval:
+      required_approvers: 1
+  - weight: 100
+actions:
+  on_error: ROLLBACK
+  on_latency: PAUSE
+  on_success: PROMOTE
+`
+
+func putTestPolicy(t *testing.T, store storage.Store) {
+	t.Helper()
+	if _, err := store.PutPolicy(context.Background(), testKey, testPolicySpec, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// putTestRollout stores rollout r1 of checkout-service in state at the
+// given decision sequence.
+func putTestRollout(t *testing.T, store storage.Store, state storage.RolloutState, seq int64) *storage.State {
+	t.Helper()
+	st := &storage.State{
+		RolloutID:     "r1",
+		ServiceID:     "checkout-service",
+		Version:       "v2",
+		State:         state,
+		Step:          1,
+		TrafficWeight: 50,
+		Sequence:      seq,
+	}
+	if err := store.Save(context.Background(), st); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func overrideRequest() *rolloutpb.OverrideRequest {
+	return &rolloutpb.OverrideRequest{ServiceId: "checkout-service", Actor: "alice", Reason: "investigating"}
+}
+
+// failingStore fails every Save with err.
+type failingStore struct {
+	storage.Store
+	err error
+}
+
+func (f *failingStore) Save(ctx context.Context, st *storage.State) error {
+	return f.err
+}
+
+func TestOverrideErrorCodes(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name   string
+		state  storage.RolloutState
+		store  func(storage.Store) storage.Store
+		req    func(*rolloutpb.OverrideRequest)
+		action func(*Server, context.Context, *rolloutpb.OverrideRequest) (*rolloutpb.OverrideResponse, error)
+		want   codes.Code
+	}{
+		{
+			name:   "missing actor",
+			state:  storage.Canary,
+			req:    func(r *rolloutpb.OverrideRequest) { r.Actor = "" },
+			action: (*Server).PauseRollout,
+			want:   codes.InvalidArgument,
+		},
+		{
+			name:   "duration on abort",
+			state:  storage.Canary,
+			req:    func(r *rolloutpb.OverrideRequest) { r.DurationSeconds = 60 },
+			action: (*Server).AbortRollout,
+			want:   codes.InvalidArgument,
+		},
+		{
+			name:   "no rollout",
+			action: (*Server).PauseRollout,
+			want:   codes.NotFound,
+		},
+		{
+			name:   "rollout replaced",
+			state:  storage.Canary,
+			req:    func(r *rolloutpb.OverrideRequest) { r.RolloutId = "r0" },
+			action: (*Server).AbortRollout,
+			want:   codes.FailedPrecondition,
+		},
+		{
+			name:   "resume while running",
+			state:  storage.Canary,
+			action: (*Server).ResumeRollout,
+			want:   codes.FailedPrecondition,
+		},
+		{
+			name:   "abort after rollback",
+			state:  storage.RolledBack,
+			action: (*Server).AbortRollout,
+			want:   codes.FailedPrecondition,
+		},
+		{
+			name:  "revision conflict",
+			state: storage.Canary,
+			store: func(s storage.Store) storage.Store {
+				return &failingStore{Store: s, err: &storage.ConflictError{Key: testKey}}
+			},
+			action: (*Server).PauseRollout,
+			want:   codes.Aborted,
+		},
+		{
+			name:   "store failure",
+			state:  storage.Canary,
+			store:  func(s storage.Store) storage.Store { return &failingStore{Store: s, err: errors.New("disk full")} },
+			action: (*Server).ForcePromote,
+			want:   codes.Internal,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var store storage.Store = storage.NewMemory()
+			putTestPolicy(t, store)
+			if c.state != "" {
+				putTestRollout(t, store, c.state, 0)
+			}
+			if c.store != nil {
+				store = c.store(store)
+			}
+			s := NewServer(store, rollout.NewMachine())
+
+			req := overrideRequest()
+			if c.req != nil {
+				c.req(req)
+			}
+			_, err := c.action(s, ctx, req)
+			if status.Code(err) != c.want {
+				t.Fatalf("expected %s, got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestOverrideQueuesManualDecisionWithTheNextSequence(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestServer(t)
+	putTestPolicy(t, store)
+	putTestRollout(t, store, storage.Canary, 4)
+
+	req := overrideRequest()
+	req.RolloutId = "r1"
+	resp, err := s.PauseRollout(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Rollout.State != string(storage.Paused) || resp.Rollout.Sequence != 5 {
+		t.Fatalf("expected PAUSED at sequence 5, got %s at %d", resp.Rollout.State, resp.Rollout.Sequence)
+	}
+
+	msgs, err := store.PendingOutbox(ctx, testKey, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("expected one queued decision, got %d", len(msgs))
+	}
+	var event rolloutpb.DecisionEvent
+	if err := proto.Unmarshal(msgs[0].Payload, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Source != rolloutpb.DecisionSource_MANUAL || event.Decision != rolloutpb.DecisionType_PAUSE ||
+		event.Actor != "alice" || event.Reason != "investigating" || event.Sequence != 5 ||
+		event.RolloutId != "r1" || event.DecisionId != "r1/manual/2" || msgs[0].DecisionID != event.DecisionId {
+		t.Fatalf("unexpected manual decision: %+v", &event)
+	}
+
+	entries, err := store.DecisionHistory(ctx, testKey, 5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Kind != storage.HistoryManual || entries[0].DecisionID != event.DecisionId ||
+		entries[0].From != storage.Canary || entries[0].To != storage.Paused {
+		t.Fatalf("expected the pause in the history as decision 5, got %+v", entries)
+	}
+}
+
+func TestOverridesAtAnApprovalGate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pause", func(t *testing.T) {
+		s, store := newTestServer(t)
+		putTestPolicy(t, store)
+		putTestRollout(t, store, storage.AwaitingApproval, 0)
+
+		resp, err := s.PauseRollout(ctx, overrideRequest())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Rollout.State != string(storage.Paused) || resp.Rollout.Override == nil {
+			t.Fatalf("expected a held PAUSED rollout, got %+v", resp.Rollout)
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		s, store := newTestServer(t)
+		putTestPolicy(t, store)
+		putTestRollout(t, store, storage.AwaitingApproval, 0)
+
+		resp, err := s.AbortRollout(ctx, overrideRequest())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Rollout.State != string(storage.RolledBack) || resp.Rollout.TrafficWeight != 0 {
+			t.Fatalf("expected ROLLED_BACK at 0%%, got %s at %d%%", resp.Rollout.State, resp.Rollout.TrafficWeight)
+		}
+	})
+}
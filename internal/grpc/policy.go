@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+func (s *Server) PutPolicy(
+	ctx context.Context,
+	req *rolloutpb.PutPolicyRequest,
+) (*rolloutpb.PutPolicyResponse, error) {
+	if req.ServiceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id is required")
+	}
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := decision.ParsePolicy([]byte(req.SpecYaml))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid policy: %v", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid policy: %v", err)
+	}
+	if policy.Key() != key {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"policy for %q does not match tenant and service_id %q", policy.Key(), key)
+	}
+
+	rec, err := s.store.PutPolicy(ctx, key, req.SpecYaml, req.ExpectedVersion)
+	if err != nil {
+		return nil, policyError(err)
+	}
+
+	return &rolloutpb.PutPolicyResponse{Policy: toPolicyPB(rec)}, nil
+}
+
+func (s *Server) GetPolicy(
+	ctx context.Context,
+	req *rolloutpb.GetPolicyRequest,
+) (*rolloutpb.GetPolicyResponse, error) {
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.store.GetPolicy(ctx, key)
+	if err != nil {
+		return nil, policyError(err)
+	}
+	if rec == nil {
+		return nil, status.Errorf(codes.NotFound, "no policy for service %q", key)
+	}
+
+	return &rolloutpb.GetPolicyResponse{Policy: toPolicyPB(rec)}, nil
+}
+
+func (s *Server) ListPolicies(
+	ctx context.Context,
+	req *rolloutpb.ListPoliciesRequest,
+) (*rolloutpb.ListPoliciesResponse, error) {
+	if err := storage.ValidateTenant(req.Tenant); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	recs, err := s.store.ListPolicies(ctx)
+	if err != nil {
+		return nil, policyError(err)
+	}
+
+	resp := &rolloutpb.ListPoliciesResponse{}
+	for _, rec := range recs {
+		if req.Tenant != "" && rec.Tenant != req.Tenant {
+			continue
+		}
+		resp.Policies = append(resp.Policies, toPolicyPB(rec))
+	}
+	return resp, nil
+}
+
+func (s *Server) DeletePolicy(
+	ctx context.Context,
+	req *rolloutpb.DeletePolicyRequest,
+) (*rolloutpb.DeletePolicyResponse, error) {
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.store.DeletePolicy(ctx, key, req.ExpectedVersion)
+	if err != nil {
+		return nil, policyError(err)
+	}
+
+	return &rolloutpb.DeletePolicyResponse{Deleted: deleted}, nil
+}
+
+func policyError(err error) error {
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toPolicyPB(rec *storage.PolicyRecord) *rolloutpb.Policy {
+	return &rolloutpb.Policy{
+		Tenant:        rec.Tenant,
+		ServiceId:     rec.ServiceID,
+		Version:       rec.Version,
+		SpecYaml:      rec.Spec,
+		UpdatedUnixMs: rec.UpdatedAt,
+	}
+}
@@ -28,6 +28,7 @@ const (
 	DecisionType_PROMOTE          DecisionType = 1
 	DecisionType_PAUSE            DecisionType = 2
 	DecisionType_ROLLBACK         DecisionType = 3
+	DecisionType_RESUME           DecisionType = 4
 )
 
 // Enum value maps for DecisionType.
@@ -37,12 +38,14 @@ var (
 		1: "PROMOTE",
 		2: "PAUSE",
 		3: "ROLLBACK",
+		4: "RESUME",
 	}
 	DecisionType_value = map[string]int32{
 		"DECISION_UNKNOWN": 0,
 		"PROMOTE":          1,
 		"PAUSE":            2,
 		"ROLLBACK":         3,
+		"RESUME":           4,
 	}
 )
 
@@ -73,12 +76,66 @@ func (DecisionType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_rollout_proto_rawDescGZIP(), []int{0}
 }
 
+type DecisionSource int32
+
+const (
+	DecisionSource_SOURCE_UNKNOWN DecisionSource = 0
+	DecisionSource_AUTOMATED      DecisionSource = 1
+	DecisionSource_MANUAL         DecisionSource = 2
+)
+
+// Enum value maps for DecisionSource.
+var (
+	DecisionSource_name = map[int32]string{
+		0: "SOURCE_UNKNOWN",
+		1: "AUTOMATED",
+		2: "MANUAL",
+	}
+	DecisionSource_value = map[string]int32{
+		"SOURCE_UNKNOWN": 0,
+		"AUTOMATED":      1,
+		"MANUAL":         2,
+	}
+)
+
+func (x DecisionSource) Enum() *DecisionSource {
+	p := new(DecisionSource)
+	*p = x
+	return p
+}
+
+func (x DecisionSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DecisionSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_rollout_proto_enumTypes[1].Descriptor()
+}
+
+func (DecisionSource) Type() protoreflect.EnumType {
+	return &file_proto_rollout_proto_enumTypes[1]
+}
+
+func (x DecisionSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DecisionSource.Descriptor instead.
+func (DecisionSource) EnumDescriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{1}
+}
+
 type StartRolloutRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId       string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Version         string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Tenant          string                 `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	BaselineVersion string                 `protobuf:"bytes,4,opt,name=baseline_version,json=baselineVersion,proto3" json:"baseline_version,omitempty"`
+	Supersede       bool                   `protobuf:"varint,5,opt,name=supersede,proto3" json:"supersede,omitempty"`
+	Actor           string                 `protobuf:"bytes,6,opt,name=actor,proto3" json:"actor,omitempty"`
+	Labels          map[string]string      `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *StartRolloutRequest) Reset() {
@@ -125,11 +182,50 @@ func (x *StartRolloutRequest) GetVersion() string {
 	return ""
 }
 
+func (x *StartRolloutRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *StartRolloutRequest) GetBaselineVersion() string {
+	if x != nil {
+		return x.BaselineVersion
+	}
+	return ""
+}
+
+func (x *StartRolloutRequest) GetSupersede() bool {
+	if x != nil {
+		return x.Supersede
+	}
+	return false
+}
+
+func (x *StartRolloutRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *StartRolloutRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
 type StartRolloutResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Accepted        bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	RolloutId       string                 `protobuf:"bytes,2,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	RejectionReason string                 `protobuf:"bytes,3,opt,name=rejection_reason,json=rejectionReason,proto3" json:"rejection_reason,omitempty"`
+	State           string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	TrafficWeight   int32                  `protobuf:"varint,5,opt,name=traffic_weight,json=trafficWeight,proto3" json:"traffic_weight,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *StartRolloutResponse) Reset() {
@@ -169,9 +265,39 @@ func (x *StartRolloutResponse) GetAccepted() bool {
 	return false
 }
 
+func (x *StartRolloutResponse) GetRolloutId() string {
+	if x != nil {
+		return x.RolloutId
+	}
+	return ""
+}
+
+func (x *StartRolloutResponse) GetRejectionReason() string {
+	if x != nil {
+		return x.RejectionReason
+	}
+	return ""
+}
+
+func (x *StartRolloutResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *StartRolloutResponse) GetTrafficWeight() int32 {
+	if x != nil {
+		return x.TrafficWeight
+	}
+	return 0
+}
+
 type StreamDecisionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Tenant        string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	FromSequence  int64                  `protobuf:"varint,3,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -213,14 +339,32 @@ func (x *StreamDecisionsRequest) GetServiceId() string {
 	return ""
 }
 
+func (x *StreamDecisionsRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *StreamDecisionsRequest) GetFromSequence() int64 {
+	if x != nil {
+		return x.FromSequence
+	}
+	return 0
+}
+
 type TelemetryEvent struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	ServiceId       string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
-	LatencyMs       float64                `protobuf:"fixed64,2,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
-	Error           bool                   `protobuf:"varint,3,opt,name=error,proto3" json:"error,omitempty"`
-	TimestampUnixMs int64                  `protobuf:"varint,4,opt,name=timestamp_unix_ms,json=timestampUnixMs,proto3" json:"timestamp_unix_ms,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId         string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	LatencyMs         float64                `protobuf:"fixed64,2,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Error             bool                   `protobuf:"varint,3,opt,name=error,proto3" json:"error,omitempty"`
+	TimestampUnixMs   int64                  `protobuf:"varint,4,opt,name=timestamp_unix_ms,json=timestampUnixMs,proto3" json:"timestamp_unix_ms,omitempty"`
+	Track             string                 `protobuf:"bytes,5,opt,name=track,proto3" json:"track,omitempty"`
+	Counters          map[string]float64     `protobuf:"bytes,6,rep,name=counters,proto3" json:"counters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	FailingDependency string                 `protobuf:"bytes,7,opt,name=failing_dependency,json=failingDependency,proto3" json:"failing_dependency,omitempty"`
+	Tenant            string                 `protobuf:"bytes,8,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *TelemetryEvent) Reset() {
@@ -281,6 +425,34 @@ func (x *TelemetryEvent) GetTimestampUnixMs() int64 {
 	return 0
 }
 
+func (x *TelemetryEvent) GetTrack() string {
+	if x != nil {
+		return x.Track
+	}
+	return ""
+}
+
+func (x *TelemetryEvent) GetCounters() map[string]float64 {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
+func (x *TelemetryEvent) GetFailingDependency() string {
+	if x != nil {
+		return x.FailingDependency
+	}
+	return ""
+}
+
+func (x *TelemetryEvent) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
 type AggregatedMetrics struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	ServiceId         string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
@@ -363,6 +535,13 @@ type DecisionEvent struct {
 	Decision        DecisionType           `protobuf:"varint,2,opt,name=decision,proto3,enum=rollout.v1.DecisionType" json:"decision,omitempty"`
 	Reason          string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 	TimestampUnixMs int64                  `protobuf:"varint,4,opt,name=timestamp_unix_ms,json=timestampUnixMs,proto3" json:"timestamp_unix_ms,omitempty"`
+	TrafficWeight   int32                  `protobuf:"varint,5,opt,name=traffic_weight,json=trafficWeight,proto3" json:"traffic_weight,omitempty"`
+	Tenant          string                 `protobuf:"bytes,6,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	RolloutId       string                 `protobuf:"bytes,7,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	DecisionId      string                 `protobuf:"bytes,8,opt,name=decision_id,json=decisionId,proto3" json:"decision_id,omitempty"`
+	Source          DecisionSource         `protobuf:"varint,9,opt,name=source,proto3,enum=rollout.v1.DecisionSource" json:"source,omitempty"`
+	Actor           string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Sequence        int64                  `protobuf:"varint,11,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -425,50 +604,1869 @@ func (x *DecisionEvent) GetTimestampUnixMs() int64 {
 	return 0
 }
 
-var File_proto_rollout_proto protoreflect.FileDescriptor
+func (x *DecisionEvent) GetTrafficWeight() int32 {
+	if x != nil {
+		return x.TrafficWeight
+	}
+	return 0
+}
 
-const file_proto_rollout_proto_rawDesc = "" +
-	"\n" +
-	"\x13proto/rollout.proto\x12\n" +
-	"rollout.v1\"N\n" +
-	"\x13StartRolloutRequest\x12\x1d\n" +
-	"\n" +
-	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\tR\aversion\"2\n" +
-	"\x14StartRolloutResponse\x12\x1a\n" +
-	"\baccepted\x18\x01 \x01(\bR\baccepted\"7\n" +
-	"\x16StreamDecisionsRequest\x12\x1d\n" +
-	"\n" +
-	"service_id\x18\x01 \x01(\tR\tserviceId\"\x90\x01\n" +
-	"\x0eTelemetryEvent\x12\x1d\n" +
-	"\n" +
-	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x1d\n" +
-	"\n" +
-	"latency_ms\x18\x02 \x01(\x01R\tlatencyMs\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\bR\x05error\x12*\n" +
-	"\x11timestamp_unix_ms\x18\x04 \x01(\x03R\x0ftimestampUnixMs\"\xd5\x01\n" +
-	"\x11AggregatedMetrics\x12\x1d\n" +
-	"\n" +
-	"service_id\x18\x01 \x01(\tR\tserviceId\x12$\n" +
-	"\x0ep95_latency_ms\x18\x02 \x01(\x01R\fp95LatencyMs\x12\x1d\n" +
-	"\n" +
-	"error_rate\x18\x03 \x01(\x01R\terrorRate\x12/\n" +
-	"\x14window_start_unix_ms\x18\x04 \x01(\x03R\x11windowStartUnixMs\x12+\n" +
-	"\x12window_end_unix_ms\x18\x05 \x01(\x03R\x0fwindowEndUnixMs\"\xa8\x01\n" +
-	"\rDecisionEvent\x12\x1d\n" +
-	"\n" +
-	"service_id\x18\x01 \x01(\tR\tserviceId\x124\n" +
-	"\bdecision\x18\x02 \x01(\x0e2\x18.rollout.v1.DecisionTypeR\bdecision\x12\x16\n" +
-	"\x06reason\x18\x03 \x01(\tR\x06reason\x12*\n" +
-	"\x11timestamp_unix_ms\x18\x04 \x01(\x03R\x0ftimestampUnixMs*J\n" +
-	"\fDecisionType\x12\x14\n" +
-	"\x10DECISION_UNKNOWN\x10\x00\x12\v\n" +
-	"\aPROMOTE\x10\x01\x12\t\n" +
-	"\x05PAUSE\x10\x02\x12\f\n" +
-	"\bROLLBACK\x10\x032\xb7\x01\n" +
-	"\x0eRolloutControl\x12Q\n" +
-	"\fStartRollout\x12\x1f.rollout.v1.StartRolloutRequest\x1a .rollout.v1.StartRolloutResponse\x12R\n" +
-	"\x0fStreamDecisions\x12\".rollout.v1.StreamDecisionsRequest\x1a\x19.rollout.v1.DecisionEvent0\x01BNZLgithub.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpbb\x06proto3"
+func (x *DecisionEvent) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *DecisionEvent) GetRolloutId() string {
+	if x != nil {
+		return x.RolloutId
+	}
+	return ""
+}
+
+func (x *DecisionEvent) GetDecisionId() string {
+	if x != nil {
+		return x.DecisionId
+	}
+	return ""
+}
+
+func (x *DecisionEvent) GetSource() DecisionSource {
+	if x != nil {
+		return x.Source
+	}
+	return DecisionSource_SOURCE_UNKNOWN
+}
+
+func (x *DecisionEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *DecisionEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type Policy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	SpecYaml      string                 `protobuf:"bytes,3,opt,name=spec_yaml,json=specYaml,proto3" json:"spec_yaml,omitempty"`
+	UpdatedUnixMs int64                  `protobuf:"varint,4,opt,name=updated_unix_ms,json=updatedUnixMs,proto3" json:"updated_unix_ms,omitempty"`
+	Tenant        string                 `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_proto_rollout_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Policy) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *Policy) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Policy) GetSpecYaml() string {
+	if x != nil {
+		return x.SpecYaml
+	}
+	return ""
+}
+
+func (x *Policy) GetUpdatedUnixMs() int64 {
+	if x != nil {
+		return x.UpdatedUnixMs
+	}
+	return 0
+}
+
+func (x *Policy) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type PutPolicyRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId       string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	SpecYaml        string                 `protobuf:"bytes,2,opt,name=spec_yaml,json=specYaml,proto3" json:"spec_yaml,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	Tenant          string                 `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PutPolicyRequest) Reset() {
+	*x = PutPolicyRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutPolicyRequest) ProtoMessage() {}
+
+func (x *PutPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutPolicyRequest.ProtoReflect.Descriptor instead.
+func (*PutPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PutPolicyRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *PutPolicyRequest) GetSpecYaml() string {
+	if x != nil {
+		return x.SpecYaml
+	}
+	return ""
+}
+
+func (x *PutPolicyRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *PutPolicyRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type PutPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *Policy                `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutPolicyResponse) Reset() {
+	*x = PutPolicyResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutPolicyResponse) ProtoMessage() {}
+
+func (x *PutPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutPolicyResponse.ProtoReflect.Descriptor instead.
+func (*PutPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PutPolicyResponse) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type GetPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Tenant        string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPolicyRequest) Reset() {
+	*x = GetPolicyRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPolicyRequest) ProtoMessage() {}
+
+func (x *GetPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPolicyRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *GetPolicyRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type GetPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *Policy                `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPolicyResponse) Reset() {
+	*x = GetPolicyResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPolicyResponse) ProtoMessage() {}
+
+func (x *GetPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPolicyResponse.ProtoReflect.Descriptor instead.
+func (*GetPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetPolicyResponse) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type ListPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        string                 `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesRequest) Reset() {
+	*x = ListPoliciesRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesRequest) ProtoMessage() {}
+
+func (x *ListPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListPoliciesRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type ListPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*Policy              `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesResponse) Reset() {
+	*x = ListPoliciesResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesResponse) ProtoMessage() {}
+
+func (x *ListPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListPoliciesResponse) GetPolicies() []*Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type DeletePolicyRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId       string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	Tenant          string                 `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeletePolicyRequest) Reset() {
+	*x = DeletePolicyRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePolicyRequest) ProtoMessage() {}
+
+func (x *DeletePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePolicyRequest.ProtoReflect.Descriptor instead.
+func (*DeletePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeletePolicyRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *DeletePolicyRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *DeletePolicyRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type DeletePolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePolicyResponse) Reset() {
+	*x = DeletePolicyResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePolicyResponse) ProtoMessage() {}
+
+func (x *DeletePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePolicyResponse.ProtoReflect.Descriptor instead.
+func (*DeletePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeletePolicyResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type ApproveRolloutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Approver      string                 `protobuf:"bytes,2,opt,name=approver,proto3" json:"approver,omitempty"`
+	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Tenant        string                 `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveRolloutRequest) Reset() {
+	*x = ApproveRolloutRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveRolloutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveRolloutRequest) ProtoMessage() {}
+
+func (x *ApproveRolloutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveRolloutRequest.ProtoReflect.Descriptor instead.
+func (*ApproveRolloutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ApproveRolloutRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ApproveRolloutRequest) GetApprover() string {
+	if x != nil {
+		return x.Approver
+	}
+	return ""
+}
+
+func (x *ApproveRolloutRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *ApproveRolloutRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type ApproveRolloutResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	State             string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Approvals         int32                  `protobuf:"varint,2,opt,name=approvals,proto3" json:"approvals,omitempty"`
+	RequiredApprovers int32                  `protobuf:"varint,3,opt,name=required_approvers,json=requiredApprovers,proto3" json:"required_approvers,omitempty"`
+	TrafficWeight     int32                  `protobuf:"varint,4,opt,name=traffic_weight,json=trafficWeight,proto3" json:"traffic_weight,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ApproveRolloutResponse) Reset() {
+	*x = ApproveRolloutResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveRolloutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveRolloutResponse) ProtoMessage() {}
+
+func (x *ApproveRolloutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveRolloutResponse.ProtoReflect.Descriptor instead.
+func (*ApproveRolloutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ApproveRolloutResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ApproveRolloutResponse) GetApprovals() int32 {
+	if x != nil {
+		return x.Approvals
+	}
+	return 0
+}
+
+func (x *ApproveRolloutResponse) GetRequiredApprovers() int32 {
+	if x != nil {
+		return x.RequiredApprovers
+	}
+	return 0
+}
+
+func (x *ApproveRolloutResponse) GetTrafficWeight() int32 {
+	if x != nil {
+		return x.TrafficWeight
+	}
+	return 0
+}
+
+type HistoryEntry struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind            string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Actor           string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	FromState       string                 `protobuf:"bytes,4,opt,name=from_state,json=fromState,proto3" json:"from_state,omitempty"`
+	ToState         string                 `protobuf:"bytes,5,opt,name=to_state,json=toState,proto3" json:"to_state,omitempty"`
+	Verdict         string                 `protobuf:"bytes,6,opt,name=verdict,proto3" json:"verdict,omitempty"`
+	Decision        string                 `protobuf:"bytes,7,opt,name=decision,proto3" json:"decision,omitempty"`
+	Reason          string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	Comment         string                 `protobuf:"bytes,9,opt,name=comment,proto3" json:"comment,omitempty"`
+	PolicyVersion   int64                  `protobuf:"varint,10,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`
+	TrafficWeight   int32                  `protobuf:"varint,11,opt,name=traffic_weight,json=trafficWeight,proto3" json:"traffic_weight,omitempty"`
+	Metrics         map[string]float64     `protobuf:"bytes,12,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	TimestampUnixMs int64                  `protobuf:"varint,13,opt,name=timestamp_unix_ms,json=timestampUnixMs,proto3" json:"timestamp_unix_ms,omitempty"`
+	RolloutId       string                 `protobuf:"bytes,14,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	DecisionId      string                 `protobuf:"bytes,15,opt,name=decision_id,json=decisionId,proto3" json:"decision_id,omitempty"`
+	Sequence        int64                  `protobuf:"varint,16,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	mi := &file_proto_rollout_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEntry) ProtoMessage() {}
+
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HistoryEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetFromState() string {
+	if x != nil {
+		return x.FromState
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetToState() string {
+	if x != nil {
+		return x.ToState
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetVerdict() string {
+	if x != nil {
+		return x.Verdict
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetPolicyVersion() int64 {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetTrafficWeight() int32 {
+	if x != nil {
+		return x.TrafficWeight
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetMetrics() map[string]float64 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *HistoryEntry) GetTimestampUnixMs() int64 {
+	if x != nil {
+		return x.TimestampUnixMs
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetRolloutId() string {
+	if x != nil {
+		return x.RolloutId
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetDecisionId() string {
+	if x != nil {
+		return x.DecisionId
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type GetRolloutHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	StartUnixMs   int64                  `protobuf:"varint,2,opt,name=start_unix_ms,json=startUnixMs,proto3" json:"start_unix_ms,omitempty"`
+	EndUnixMs     int64                  `protobuf:"varint,3,opt,name=end_unix_ms,json=endUnixMs,proto3" json:"end_unix_ms,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Tenant        string                 `protobuf:"bytes,6,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRolloutHistoryRequest) Reset() {
+	*x = GetRolloutHistoryRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRolloutHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRolloutHistoryRequest) ProtoMessage() {}
+
+func (x *GetRolloutHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRolloutHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRolloutHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetRolloutHistoryRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *GetRolloutHistoryRequest) GetStartUnixMs() int64 {
+	if x != nil {
+		return x.StartUnixMs
+	}
+	return 0
+}
+
+func (x *GetRolloutHistoryRequest) GetEndUnixMs() int64 {
+	if x != nil {
+		return x.EndUnixMs
+	}
+	return 0
+}
+
+func (x *GetRolloutHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetRolloutHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetRolloutHistoryRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type GetRolloutHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*HistoryEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRolloutHistoryResponse) Reset() {
+	*x = GetRolloutHistoryResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRolloutHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRolloutHistoryResponse) ProtoMessage() {}
+
+func (x *GetRolloutHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRolloutHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetRolloutHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRolloutHistoryResponse) GetEntries() []*HistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetRolloutHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Verdict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Verdict       string                 `protobuf:"bytes,1,opt,name=verdict,proto3" json:"verdict,omitempty"`
+	Decision      string                 `protobuf:"bytes,2,opt,name=decision,proto3" json:"decision,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Metrics       map[string]float64     `protobuf:"bytes,4,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	DecidedUnixMs int64                  `protobuf:"varint,5,opt,name=decided_unix_ms,json=decidedUnixMs,proto3" json:"decided_unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Verdict) Reset() {
+	*x = Verdict{}
+	mi := &file_proto_rollout_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Verdict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Verdict) ProtoMessage() {}
+
+func (x *Verdict) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Verdict.ProtoReflect.Descriptor instead.
+func (*Verdict) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Verdict) GetVerdict() string {
+	if x != nil {
+		return x.Verdict
+	}
+	return ""
+}
+
+func (x *Verdict) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *Verdict) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Verdict) GetMetrics() map[string]float64 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *Verdict) GetDecidedUnixMs() int64 {
+	if x != nil {
+		return x.DecidedUnixMs
+	}
+	return 0
+}
+
+type Rollout struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RolloutId       string                 `protobuf:"bytes,1,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	Tenant          string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	ServiceId       string                 `protobuf:"bytes,3,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Version         string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	BaselineVersion string                 `protobuf:"bytes,5,opt,name=baseline_version,json=baselineVersion,proto3" json:"baseline_version,omitempty"`
+	Labels          map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	State           string                 `protobuf:"bytes,7,opt,name=state,proto3" json:"state,omitempty"`
+	Step            int32                  `protobuf:"varint,8,opt,name=step,proto3" json:"step,omitempty"`
+	TrafficWeight   int32                  `protobuf:"varint,9,opt,name=traffic_weight,json=trafficWeight,proto3" json:"traffic_weight,omitempty"`
+	LastVerdict     *Verdict               `protobuf:"bytes,10,opt,name=last_verdict,json=lastVerdict,proto3" json:"last_verdict,omitempty"`
+	StartedUnixMs   int64                  `protobuf:"varint,11,opt,name=started_unix_ms,json=startedUnixMs,proto3" json:"started_unix_ms,omitempty"`
+	UpdatedUnixMs   int64                  `protobuf:"varint,12,opt,name=updated_unix_ms,json=updatedUnixMs,proto3" json:"updated_unix_ms,omitempty"`
+	FinishedUnixMs  int64                  `protobuf:"varint,13,opt,name=finished_unix_ms,json=finishedUnixMs,proto3" json:"finished_unix_ms,omitempty"`
+	Override        *Override              `protobuf:"bytes,14,opt,name=override,proto3" json:"override,omitempty"`
+	Sequence        int64                  `protobuf:"varint,15,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Rollout) Reset() {
+	*x = Rollout{}
+	mi := &file_proto_rollout_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rollout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollout) ProtoMessage() {}
+
+func (x *Rollout) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollout.ProtoReflect.Descriptor instead.
+func (*Rollout) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Rollout) GetRolloutId() string {
+	if x != nil {
+		return x.RolloutId
+	}
+	return ""
+}
+
+func (x *Rollout) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Rollout) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *Rollout) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Rollout) GetBaselineVersion() string {
+	if x != nil {
+		return x.BaselineVersion
+	}
+	return ""
+}
+
+func (x *Rollout) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Rollout) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Rollout) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *Rollout) GetTrafficWeight() int32 {
+	if x != nil {
+		return x.TrafficWeight
+	}
+	return 0
+}
+
+func (x *Rollout) GetLastVerdict() *Verdict {
+	if x != nil {
+		return x.LastVerdict
+	}
+	return nil
+}
+
+func (x *Rollout) GetStartedUnixMs() int64 {
+	if x != nil {
+		return x.StartedUnixMs
+	}
+	return 0
+}
+
+func (x *Rollout) GetUpdatedUnixMs() int64 {
+	if x != nil {
+		return x.UpdatedUnixMs
+	}
+	return 0
+}
+
+func (x *Rollout) GetFinishedUnixMs() int64 {
+	if x != nil {
+		return x.FinishedUnixMs
+	}
+	return 0
+}
+
+func (x *Rollout) GetOverride() *Override {
+	if x != nil {
+		return x.Override
+	}
+	return nil
+}
+
+func (x *Rollout) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type Override struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Actor         string                 `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	SetUnixMs     int64                  `protobuf:"varint,4,opt,name=set_unix_ms,json=setUnixMs,proto3" json:"set_unix_ms,omitempty"`
+	ExpiresUnixMs int64                  `protobuf:"varint,5,opt,name=expires_unix_ms,json=expiresUnixMs,proto3" json:"expires_unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Override) Reset() {
+	*x = Override{}
+	mi := &file_proto_rollout_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Override) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Override) ProtoMessage() {}
+
+func (x *Override) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Override.ProtoReflect.Descriptor instead.
+func (*Override) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Override) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Override) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *Override) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Override) GetSetUnixMs() int64 {
+	if x != nil {
+		return x.SetUnixMs
+	}
+	return 0
+}
+
+func (x *Override) GetExpiresUnixMs() int64 {
+	if x != nil {
+		return x.ExpiresUnixMs
+	}
+	return 0
+}
+
+type GetRolloutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Tenant        string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRolloutRequest) Reset() {
+	*x = GetRolloutRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRolloutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRolloutRequest) ProtoMessage() {}
+
+func (x *GetRolloutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRolloutRequest.ProtoReflect.Descriptor instead.
+func (*GetRolloutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetRolloutRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *GetRolloutRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type GetRolloutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rollout       *Rollout               `protobuf:"bytes,1,opt,name=rollout,proto3" json:"rollout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRolloutResponse) Reset() {
+	*x = GetRolloutResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRolloutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRolloutResponse) ProtoMessage() {}
+
+func (x *GetRolloutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRolloutResponse.ProtoReflect.Descriptor instead.
+func (*GetRolloutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetRolloutResponse) GetRollout() *Rollout {
+	if x != nil {
+		return x.Rollout
+	}
+	return nil
+}
+
+type ListRolloutsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        string                 `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	States        []string               `protobuf:"bytes,2,rep,name=states,proto3" json:"states,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolloutsRequest) Reset() {
+	*x = ListRolloutsRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolloutsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolloutsRequest) ProtoMessage() {}
+
+func (x *ListRolloutsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolloutsRequest.ProtoReflect.Descriptor instead.
+func (*ListRolloutsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListRolloutsRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ListRolloutsRequest) GetStates() []string {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *ListRolloutsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListRolloutsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRolloutsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListRolloutsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rollouts      []*Rollout             `protobuf:"bytes,1,rep,name=rollouts,proto3" json:"rollouts,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolloutsResponse) Reset() {
+	*x = ListRolloutsResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolloutsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolloutsResponse) ProtoMessage() {}
+
+func (x *ListRolloutsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolloutsResponse.ProtoReflect.Descriptor instead.
+func (*ListRolloutsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListRolloutsResponse) GetRollouts() []*Rollout {
+	if x != nil {
+		return x.Rollouts
+	}
+	return nil
+}
+
+func (x *ListRolloutsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type OverrideRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId       string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Tenant          string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Actor           string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason          string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	RolloutId       string                 `protobuf:"bytes,5,opt,name=rollout_id,json=rolloutId,proto3" json:"rollout_id,omitempty"`
+	DurationSeconds int64                  `protobuf:"varint,6,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *OverrideRequest) Reset() {
+	*x = OverrideRequest{}
+	mi := &file_proto_rollout_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverrideRequest) ProtoMessage() {}
+
+func (x *OverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverrideRequest.ProtoReflect.Descriptor instead.
+func (*OverrideRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *OverrideRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *OverrideRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *OverrideRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *OverrideRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OverrideRequest) GetRolloutId() string {
+	if x != nil {
+		return x.RolloutId
+	}
+	return ""
+}
+
+func (x *OverrideRequest) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type OverrideResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rollout       *Rollout               `protobuf:"bytes,1,opt,name=rollout,proto3" json:"rollout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OverrideResponse) Reset() {
+	*x = OverrideResponse{}
+	mi := &file_proto_rollout_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverrideResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverrideResponse) ProtoMessage() {}
+
+func (x *OverrideResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rollout_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverrideResponse.ProtoReflect.Descriptor instead.
+func (*OverrideResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rollout_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *OverrideResponse) GetRollout() *Rollout {
+	if x != nil {
+		return x.Rollout
+	}
+	return nil
+}
+
+var File_proto_rollout_proto protoreflect.FileDescriptor
+
+const file_proto_rollout_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/rollout.proto\x12\n" +
+	"rollout.v1\"\xc5\x02\n" +
+	"\x13StartRolloutRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06tenant\x18\x03 \x01(\tR\x06tenant\x12)\n" +
+	"\x10baseline_version\x18\x04 \x01(\tR\x0fbaselineVersion\x12\x1c\n" +
+	"\tsupersede\x18\x05 \x01(\bR\tsupersede\x12\x14\n" +
+	"\x05actor\x18\x06 \x01(\tR\x05actor\x12C\n" +
+	"\x06labels\x18\a \x03(\v2+.rollout.v1.StartRolloutRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb9\x01\n" +
+	"\x14StartRolloutResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x02 \x01(\tR\trolloutId\x12)\n" +
+	"\x10rejection_reason\x18\x03 \x01(\tR\x0frejectionReason\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12%\n" +
+	"\x0etraffic_weight\x18\x05 \x01(\x05R\rtrafficWeight\"t\n" +
+	"\x16StreamDecisionsRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x12#\n" +
+	"\rfrom_sequence\x18\x03 \x01(\x03R\ffromSequence\"\xf0\x02\n" +
+	"\x0eTelemetryEvent\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x02 \x01(\x01R\tlatencyMs\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\bR\x05error\x12*\n" +
+	"\x11timestamp_unix_ms\x18\x04 \x01(\x03R\x0ftimestampUnixMs\x12\x14\n" +
+	"\x05track\x18\x05 \x01(\tR\x05track\x12D\n" +
+	"\bcounters\x18\x06 \x03(\v2(.rollout.v1.TelemetryEvent.CountersEntryR\bcounters\x12-\n" +
+	"\x12failing_dependency\x18\a \x01(\tR\x11failingDependency\x12\x16\n" +
+	"\x06tenant\x18\b \x01(\tR\x06tenant\x1a;\n" +
+	"\rCountersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xd5\x01\n" +
+	"\x11AggregatedMetrics\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12$\n" +
+	"\x0ep95_latency_ms\x18\x02 \x01(\x01R\fp95LatencyMs\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\x03 \x01(\x01R\terrorRate\x12/\n" +
+	"\x14window_start_unix_ms\x18\x04 \x01(\x03R\x11windowStartUnixMs\x12+\n" +
+	"\x12window_end_unix_ms\x18\x05 \x01(\x03R\x0fwindowEndUnixMs\"\x8d\x03\n" +
+	"\rDecisionEvent\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x124\n" +
+	"\bdecision\x18\x02 \x01(\x0e2\x18.rollout.v1.DecisionTypeR\bdecision\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12*\n" +
+	"\x11timestamp_unix_ms\x18\x04 \x01(\x03R\x0ftimestampUnixMs\x12%\n" +
+	"\x0etraffic_weight\x18\x05 \x01(\x05R\rtrafficWeight\x12\x16\n" +
+	"\x06tenant\x18\x06 \x01(\tR\x06tenant\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\a \x01(\tR\trolloutId\x12\x1f\n" +
+	"\vdecision_id\x18\b \x01(\tR\n" +
+	"decisionId\x122\n" +
+	"\x06source\x18\t \x01(\x0e2\x1a.rollout.v1.DecisionSourceR\x06source\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x1a\n" +
+	"\bsequence\x18\v \x01(\x03R\bsequence\"\x9e\x01\n" +
+	"\x06Policy\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x1b\n" +
+	"\tspec_yaml\x18\x03 \x01(\tR\bspecYaml\x12&\n" +
+	"\x0fupdated_unix_ms\x18\x04 \x01(\x03R\rupdatedUnixMs\x12\x16\n" +
+	"\x06tenant\x18\x05 \x01(\tR\x06tenant\"\x91\x01\n" +
+	"\x10PutPolicyRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x1b\n" +
+	"\tspec_yaml\x18\x02 \x01(\tR\bspecYaml\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\x12\x16\n" +
+	"\x06tenant\x18\x04 \x01(\tR\x06tenant\"?\n" +
+	"\x11PutPolicyResponse\x12*\n" +
+	"\x06policy\x18\x01 \x01(\v2\x12.rollout.v1.PolicyR\x06policy\"I\n" +
+	"\x10GetPolicyRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\"?\n" +
+	"\x11GetPolicyResponse\x12*\n" +
+	"\x06policy\x18\x01 \x01(\v2\x12.rollout.v1.PolicyR\x06policy\"-\n" +
+	"\x13ListPoliciesRequest\x12\x16\n" +
+	"\x06tenant\x18\x01 \x01(\tR\x06tenant\"F\n" +
+	"\x14ListPoliciesResponse\x12.\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x12.rollout.v1.PolicyR\bpolicies\"w\n" +
+	"\x13DeletePolicyRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\x12\x16\n" +
+	"\x06tenant\x18\x03 \x01(\tR\x06tenant\"0\n" +
+	"\x14DeletePolicyResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"\x84\x01\n" +
+	"\x15ApproveRolloutRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x1a\n" +
+	"\bapprover\x18\x02 \x01(\tR\bapprover\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x12\x16\n" +
+	"\x06tenant\x18\x04 \x01(\tR\x06tenant\"\xa2\x01\n" +
+	"\x16ApproveRolloutResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x1c\n" +
+	"\tapprovals\x18\x02 \x01(\x05R\tapprovals\x12-\n" +
+	"\x12required_approvers\x18\x03 \x01(\x05R\x11requiredApprovers\x12%\n" +
+	"\x0etraffic_weight\x18\x04 \x01(\x05R\rtrafficWeight\"\xbd\x04\n" +
+	"\fHistoryEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x1d\n" +
+	"\n" +
+	"from_state\x18\x04 \x01(\tR\tfromState\x12\x19\n" +
+	"\bto_state\x18\x05 \x01(\tR\atoState\x12\x18\n" +
+	"\averdict\x18\x06 \x01(\tR\averdict\x12\x1a\n" +
+	"\bdecision\x18\a \x01(\tR\bdecision\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x18\n" +
+	"\acomment\x18\t \x01(\tR\acomment\x12%\n" +
+	"\x0epolicy_version\x18\n" +
+	" \x01(\x03R\rpolicyVersion\x12%\n" +
+	"\x0etraffic_weight\x18\v \x01(\x05R\rtrafficWeight\x12?\n" +
+	"\ametrics\x18\f \x03(\v2%.rollout.v1.HistoryEntry.MetricsEntryR\ametrics\x12*\n" +
+	"\x11timestamp_unix_ms\x18\r \x01(\x03R\x0ftimestampUnixMs\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x0e \x01(\tR\trolloutId\x12\x1f\n" +
+	"\vdecision_id\x18\x0f \x01(\tR\n" +
+	"decisionId\x12\x1a\n" +
+	"\bsequence\x18\x10 \x01(\x03R\bsequence\x1a:\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xd1\x01\n" +
+	"\x18GetRolloutHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\"\n" +
+	"\rstart_unix_ms\x18\x02 \x01(\x03R\vstartUnixMs\x12\x1e\n" +
+	"\vend_unix_ms\x18\x03 \x01(\x03R\tendUnixMs\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06tenant\x18\x06 \x01(\tR\x06tenant\"w\n" +
+	"\x19GetRolloutHistoryResponse\x122\n" +
+	"\aentries\x18\x01 \x03(\v2\x18.rollout.v1.HistoryEntryR\aentries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf7\x01\n" +
+	"\aVerdict\x12\x18\n" +
+	"\averdict\x18\x01 \x01(\tR\averdict\x12\x1a\n" +
+	"\bdecision\x18\x02 \x01(\tR\bdecision\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12:\n" +
+	"\ametrics\x18\x04 \x03(\v2 .rollout.v1.Verdict.MetricsEntryR\ametrics\x12&\n" +
+	"\x0fdecided_unix_ms\x18\x05 \x01(\x03R\rdecidedUnixMs\x1a:\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xe9\x04\n" +
+	"\aRollout\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x01 \x01(\tR\trolloutId\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x03 \x01(\tR\tserviceId\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12)\n" +
+	"\x10baseline_version\x18\x05 \x01(\tR\x0fbaselineVersion\x127\n" +
+	"\x06labels\x18\x06 \x03(\v2\x1f.rollout.v1.Rollout.LabelsEntryR\x06labels\x12\x14\n" +
+	"\x05state\x18\a \x01(\tR\x05state\x12\x12\n" +
+	"\x04step\x18\b \x01(\x05R\x04step\x12%\n" +
+	"\x0etraffic_weight\x18\t \x01(\x05R\rtrafficWeight\x126\n" +
+	"\flast_verdict\x18\n" +
+	" \x01(\v2\x13.rollout.v1.VerdictR\vlastVerdict\x12&\n" +
+	"\x0fstarted_unix_ms\x18\v \x01(\x03R\rstartedUnixMs\x12&\n" +
+	"\x0fupdated_unix_ms\x18\f \x01(\x03R\rupdatedUnixMs\x12(\n" +
+	"\x10finished_unix_ms\x18\r \x01(\x03R\x0efinishedUnixMs\x120\n" +
+	"\boverride\x18\x0e \x01(\v2\x14.rollout.v1.OverrideR\boverride\x12\x1a\n" +
+	"\bsequence\x18\x0f \x01(\x03R\bsequence\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x98\x01\n" +
+	"\bOverride\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x14\n" +
+	"\x05actor\x18\x02 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1e\n" +
+	"\vset_unix_ms\x18\x04 \x01(\x03R\tsetUnixMs\x12&\n" +
+	"\x0fexpires_unix_ms\x18\x05 \x01(\x03R\rexpiresUnixMs\"J\n" +
+	"\x11GetRolloutRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\"C\n" +
+	"\x12GetRolloutResponse\x12-\n" +
+	"\arollout\x18\x01 \x01(\v2\x13.rollout.v1.RolloutR\arollout\"\x81\x02\n" +
+	"\x13ListRolloutsRequest\x12\x16\n" +
+	"\x06tenant\x18\x01 \x01(\tR\x06tenant\x12\x16\n" +
+	"\x06states\x18\x02 \x03(\tR\x06states\x12C\n" +
+	"\x06labels\x18\x03 \x03(\v2+.rollout.v1.ListRolloutsRequest.LabelsEntryR\x06labels\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"o\n" +
+	"\x14ListRolloutsResponse\x12/\n" +
+	"\brollouts\x18\x01 \x03(\v2\x13.rollout.v1.RolloutR\brollouts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc0\x01\n" +
+	"\x0fOverrideRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"rollout_id\x18\x05 \x01(\tR\trolloutId\x12)\n" +
+	"\x10duration_seconds\x18\x06 \x01(\x03R\x0fdurationSeconds\"A\n" +
+	"\x10OverrideResponse\x12-\n" +
+	"\arollout\x18\x01 \x01(\v2\x13.rollout.v1.RolloutR\arollout*V\n" +
+	"\fDecisionType\x12\x14\n" +
+	"\x10DECISION_UNKNOWN\x10\x00\x12\v\n" +
+	"\aPROMOTE\x10\x01\x12\t\n" +
+	"\x05PAUSE\x10\x02\x12\f\n" +
+	"\bROLLBACK\x10\x03\x12\n" +
+	"\n" +
+	"\x06RESUME\x10\x04*?\n" +
+	"\x0eDecisionSource\x12\x12\n" +
+	"\x0eSOURCE_UNKNOWN\x10\x00\x12\r\n" +
+	"\tAUTOMATED\x10\x01\x12\n" +
+	"\n" +
+	"\x06MANUAL\x10\x022\xf9\b\n" +
+	"\x0eRolloutControl\x12Q\n" +
+	"\fStartRollout\x12\x1f.rollout.v1.StartRolloutRequest\x1a .rollout.v1.StartRolloutResponse\x12R\n" +
+	"\x0fStreamDecisions\x12\".rollout.v1.StreamDecisionsRequest\x1a\x19.rollout.v1.DecisionEvent0\x01\x12H\n" +
+	"\tPutPolicy\x12\x1c.rollout.v1.PutPolicyRequest\x1a\x1d.rollout.v1.PutPolicyResponse\x12H\n" +
+	"\tGetPolicy\x12\x1c.rollout.v1.GetPolicyRequest\x1a\x1d.rollout.v1.GetPolicyResponse\x12Q\n" +
+	"\fListPolicies\x12\x1f.rollout.v1.ListPoliciesRequest\x1a .rollout.v1.ListPoliciesResponse\x12Q\n" +
+	"\fDeletePolicy\x12\x1f.rollout.v1.DeletePolicyRequest\x1a .rollout.v1.DeletePolicyResponse\x12W\n" +
+	"\x0eApproveRollout\x12!.rollout.v1.ApproveRolloutRequest\x1a\".rollout.v1.ApproveRolloutResponse\x12`\n" +
+	"\x11GetRolloutHistory\x12$.rollout.v1.GetRolloutHistoryRequest\x1a%.rollout.v1.GetRolloutHistoryResponse\x12K\n" +
+	"\n" +
+	"GetRollout\x12\x1d.rollout.v1.GetRolloutRequest\x1a\x1e.rollout.v1.GetRolloutResponse\x12Q\n" +
+	"\fListRollouts\x12\x1f.rollout.v1.ListRolloutsRequest\x1a .rollout.v1.ListRolloutsResponse\x12I\n" +
+	"\fPauseRollout\x12\x1b.rollout.v1.OverrideRequest\x1a\x1c.rollout.v1.OverrideResponse\x12J\n" +
+	"\rResumeRollout\x12\x1b.rollout.v1.OverrideRequest\x1a\x1c.rollout.v1.OverrideResponse\x12I\n" +
+	"\fAbortRollout\x12\x1b.rollout.v1.OverrideRequest\x1a\x1c.rollout.v1.OverrideResponse\x12I\n" +
+	"\fForcePromote\x12\x1b.rollout.v1.OverrideRequest\x1a\x1c.rollout.v1.OverrideResponseBNZLgithub.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpbb\x06proto3"
 
 var (
 	file_proto_rollout_proto_rawDescOnce sync.Once
@@ -482,28 +2480,98 @@ func file_proto_rollout_proto_rawDescGZIP() []byte {
 	return file_proto_rollout_proto_rawDescData
 }
 
-var file_proto_rollout_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_rollout_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_rollout_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_rollout_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_proto_rollout_proto_goTypes = []any{
-	(DecisionType)(0),              // 0: rollout.v1.DecisionType
-	(*StartRolloutRequest)(nil),    // 1: rollout.v1.StartRolloutRequest
-	(*StartRolloutResponse)(nil),   // 2: rollout.v1.StartRolloutResponse
-	(*StreamDecisionsRequest)(nil), // 3: rollout.v1.StreamDecisionsRequest
-	(*TelemetryEvent)(nil),         // 4: rollout.v1.TelemetryEvent
-	(*AggregatedMetrics)(nil),      // 5: rollout.v1.AggregatedMetrics
-	(*DecisionEvent)(nil),          // 6: rollout.v1.DecisionEvent
+	(DecisionType)(0),                 // 0: rollout.v1.DecisionType
+	(DecisionSource)(0),               // 1: rollout.v1.DecisionSource
+	(*StartRolloutRequest)(nil),       // 2: rollout.v1.StartRolloutRequest
+	(*StartRolloutResponse)(nil),      // 3: rollout.v1.StartRolloutResponse
+	(*StreamDecisionsRequest)(nil),    // 4: rollout.v1.StreamDecisionsRequest
+	(*TelemetryEvent)(nil),            // 5: rollout.v1.TelemetryEvent
+	(*AggregatedMetrics)(nil),         // 6: rollout.v1.AggregatedMetrics
+	(*DecisionEvent)(nil),             // 7: rollout.v1.DecisionEvent
+	(*Policy)(nil),                    // 8: rollout.v1.Policy
+	(*PutPolicyRequest)(nil),          // 9: rollout.v1.PutPolicyRequest
+	(*PutPolicyResponse)(nil),         // 10: rollout.v1.PutPolicyResponse
+	(*GetPolicyRequest)(nil),          // 11: rollout.v1.GetPolicyRequest
+	(*GetPolicyResponse)(nil),         // 12: rollout.v1.GetPolicyResponse
+	(*ListPoliciesRequest)(nil),       // 13: rollout.v1.ListPoliciesRequest
+	(*ListPoliciesResponse)(nil),      // 14: rollout.v1.ListPoliciesResponse
+	(*DeletePolicyRequest)(nil),       // 15: rollout.v1.DeletePolicyRequest
+	(*DeletePolicyResponse)(nil),      // 16: rollout.v1.DeletePolicyResponse
+	(*ApproveRolloutRequest)(nil),     // 17: rollout.v1.ApproveRolloutRequest
+	(*ApproveRolloutResponse)(nil),    // 18: rollout.v1.ApproveRolloutResponse
+	(*HistoryEntry)(nil),              // 19: rollout.v1.HistoryEntry
+	(*GetRolloutHistoryRequest)(nil),  // 20: rollout.v1.GetRolloutHistoryRequest
+	(*GetRolloutHistoryResponse)(nil), // 21: rollout.v1.GetRolloutHistoryResponse
+	(*Verdict)(nil),                   // 22: rollout.v1.Verdict
+	(*Rollout)(nil),                   // 23: rollout.v1.Rollout
+	(*Override)(nil),                  // 24: rollout.v1.Override
+	(*GetRolloutRequest)(nil),         // 25: rollout.v1.GetRolloutRequest
+	(*GetRolloutResponse)(nil),        // 26: rollout.v1.GetRolloutResponse
+	(*ListRolloutsRequest)(nil),       // 27: rollout.v1.ListRolloutsRequest
+	(*ListRolloutsResponse)(nil),      // 28: rollout.v1.ListRolloutsResponse
+	(*OverrideRequest)(nil),           // 29: rollout.v1.OverrideRequest
+	(*OverrideResponse)(nil),          // 30: rollout.v1.OverrideResponse
+	nil,                               // 31: rollout.v1.StartRolloutRequest.LabelsEntry
+	nil,                               // 32: rollout.v1.TelemetryEvent.CountersEntry
+	nil,                               // 33: rollout.v1.HistoryEntry.MetricsEntry
+	nil,                               // 34: rollout.v1.Verdict.MetricsEntry
+	nil,                               // 35: rollout.v1.Rollout.LabelsEntry
+	nil,                               // 36: rollout.v1.ListRolloutsRequest.LabelsEntry
 }
 var file_proto_rollout_proto_depIdxs = []int32{
-	0, // 0: rollout.v1.DecisionEvent.decision:type_name -> rollout.v1.DecisionType
-	1, // 1: rollout.v1.RolloutControl.StartRollout:input_type -> rollout.v1.StartRolloutRequest
-	3, // 2: rollout.v1.RolloutControl.StreamDecisions:input_type -> rollout.v1.StreamDecisionsRequest
-	2, // 3: rollout.v1.RolloutControl.StartRollout:output_type -> rollout.v1.StartRolloutResponse
-	6, // 4: rollout.v1.RolloutControl.StreamDecisions:output_type -> rollout.v1.DecisionEvent
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	31, // 0: rollout.v1.StartRolloutRequest.labels:type_name -> rollout.v1.StartRolloutRequest.LabelsEntry
+	32, // 1: rollout.v1.TelemetryEvent.counters:type_name -> rollout.v1.TelemetryEvent.CountersEntry
+	0,  // 2: rollout.v1.DecisionEvent.decision:type_name -> rollout.v1.DecisionType
+	1,  // 3: rollout.v1.DecisionEvent.source:type_name -> rollout.v1.DecisionSource
+	8,  // 4: rollout.v1.PutPolicyResponse.policy:type_name -> rollout.v1.Policy
+	8,  // 5: rollout.v1.GetPolicyResponse.policy:type_name -> rollout.v1.Policy
+	8,  // 6: rollout.v1.ListPoliciesResponse.policies:type_name -> rollout.v1.Policy
+	33, // 7: rollout.v1.HistoryEntry.metrics:type_name -> rollout.v1.HistoryEntry.MetricsEntry
+	19, // 8: rollout.v1.GetRolloutHistoryResponse.entries:type_name -> rollout.v1.HistoryEntry
+	34, // 9: rollout.v1.Verdict.metrics:type_name -> rollout.v1.Verdict.MetricsEntry
+	35, // 10: rollout.v1.Rollout.labels:type_name -> rollout.v1.Rollout.LabelsEntry
+	22, // 11: rollout.v1.Rollout.last_verdict:type_name -> rollout.v1.Verdict
+	24, // 12: rollout.v1.Rollout.override:type_name -> rollout.v1.Override
+	23, // 13: rollout.v1.GetRolloutResponse.rollout:type_name -> rollout.v1.Rollout
+	36, // 14: rollout.v1.ListRolloutsRequest.labels:type_name -> rollout.v1.ListRolloutsRequest.LabelsEntry
+	23, // 15: rollout.v1.ListRolloutsResponse.rollouts:type_name -> rollout.v1.Rollout
+	23, // 16: rollout.v1.OverrideResponse.rollout:type_name -> rollout.v1.Rollout
+	2,  // 17: rollout.v1.RolloutControl.StartRollout:input_type -> rollout.v1.StartRolloutRequest
+	4,  // 18: rollout.v1.RolloutControl.StreamDecisions:input_type -> rollout.v1.StreamDecisionsRequest
+	9,  // 19: rollout.v1.RolloutControl.PutPolicy:input_type -> rollout.v1.PutPolicyRequest
+	11, // 20: rollout.v1.RolloutControl.GetPolicy:input_type -> rollout.v1.GetPolicyRequest
+	13, // 21: rollout.v1.RolloutControl.ListPolicies:input_type -> rollout.v1.ListPoliciesRequest
+	15, // 22: rollout.v1.RolloutControl.DeletePolicy:input_type -> rollout.v1.DeletePolicyRequest
+	17, // 23: rollout.v1.RolloutControl.ApproveRollout:input_type -> rollout.v1.ApproveRolloutRequest
+	20, // 24: rollout.v1.RolloutControl.GetRolloutHistory:input_type -> rollout.v1.GetRolloutHistoryRequest
+	25, // 25: rollout.v1.RolloutControl.GetRollout:input_type -> rollout.v1.GetRolloutRequest
+	27, // 26: rollout.v1.RolloutControl.ListRollouts:input_type -> rollout.v1.ListRolloutsRequest
+	29, // 27: rollout.v1.RolloutControl.PauseRollout:input_type -> rollout.v1.OverrideRequest
+	29, // 28: rollout.v1.RolloutControl.ResumeRollout:input_type -> rollout.v1.OverrideRequest
+	29, // 29: rollout.v1.RolloutControl.AbortRollout:input_type -> rollout.v1.OverrideRequest
+	29, // 30: rollout.v1.RolloutControl.ForcePromote:input_type -> rollout.v1.OverrideRequest
+	3,  // 31: rollout.v1.RolloutControl.StartRollout:output_type -> rollout.v1.StartRolloutResponse
+	7,  // 32: rollout.v1.RolloutControl.StreamDecisions:output_type -> rollout.v1.DecisionEvent
+	10, // 33: rollout.v1.RolloutControl.PutPolicy:output_type -> rollout.v1.PutPolicyResponse
+	12, // 34: rollout.v1.RolloutControl.GetPolicy:output_type -> rollout.v1.GetPolicyResponse
+	14, // 35: rollout.v1.RolloutControl.ListPolicies:output_type -> rollout.v1.ListPoliciesResponse
+	16, // 36: rollout.v1.RolloutControl.DeletePolicy:output_type -> rollout.v1.DeletePolicyResponse
+	18, // 37: rollout.v1.RolloutControl.ApproveRollout:output_type -> rollout.v1.ApproveRolloutResponse
+	21, // 38: rollout.v1.RolloutControl.GetRolloutHistory:output_type -> rollout.v1.GetRolloutHistoryResponse
+	26, // 39: rollout.v1.RolloutControl.GetRollout:output_type -> rollout.v1.GetRolloutResponse
+	28, // 40: rollout.v1.RolloutControl.ListRollouts:output_type -> rollout.v1.ListRolloutsResponse
+	30, // 41: rollout.v1.RolloutControl.PauseRollout:output_type -> rollout.v1.OverrideResponse
+	30, // 42: rollout.v1.RolloutControl.ResumeRollout:output_type -> rollout.v1.OverrideResponse
+	30, // 43: rollout.v1.RolloutControl.AbortRollout:output_type -> rollout.v1.OverrideResponse
+	30, // 44: rollout.v1.RolloutControl.ForcePromote:output_type -> rollout.v1.OverrideResponse
+	31, // [31:45] is the sub-list for method output_type
+	17, // [17:31] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_proto_rollout_proto_init() }
@@ -516,8 +2584,8 @@ func file_proto_rollout_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_rollout_proto_rawDesc), len(file_proto_rollout_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
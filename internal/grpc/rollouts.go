@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const (
+	defaultRolloutPageSize = 50
+	maxRolloutPageSize     = 500
+)
+
+func (s *Server) GetRollout(
+	ctx context.Context,
+	req *rolloutpb.GetRolloutRequest,
+) (*rolloutpb.GetRolloutResponse, error) {
+	if req.ServiceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id is required")
+	}
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if st == nil {
+		return nil, status.Errorf(codes.NotFound, "no rollout for service %q", key)
+	}
+	return &rolloutpb.GetRolloutResponse{Rollout: toRolloutPB(st)}, nil
+}
+
+func (s *Server) ListRollouts(
+	ctx context.Context,
+	req *rolloutpb.ListRolloutsRequest,
+) (*rolloutpb.ListRolloutsResponse, error) {
+	if err := storage.ValidateTenant(req.Tenant); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	f := storage.RolloutFilter{Tenant: req.Tenant, Labels: req.Labels}
+	for _, name := range req.States {
+		state := storage.RolloutState(name)
+		if !rollout.IsKnown(state) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown rollout state %q", name)
+		}
+		f.States = append(f.States, state)
+	}
+
+	size := int64(req.PageSize)
+	switch {
+	case size <= 0:
+		size = defaultRolloutPageSize
+	case size > maxRolloutPageSize:
+		size = maxRolloutPageSize
+	}
+
+	states, next, err := s.store.ListRollouts(ctx, f, req.PageToken, size)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &rolloutpb.ListRolloutsResponse{NextPageToken: next}
+	for _, st := range states {
+		resp.Rollouts = append(resp.Rollouts, toRolloutPB(st))
+	}
+	return resp, nil
+}
+
+func toRolloutPB(st *storage.State) *rolloutpb.Rollout {
+	tenant, _ := storage.SplitKey(st.Key())
+	r := &rolloutpb.Rollout{
+		RolloutId:       st.RolloutID,
+		Tenant:          tenant,
+		ServiceId:       st.ServiceID,
+		Version:         st.Version,
+		BaselineVersion: st.BaselineVersion,
+		Labels:          st.Labels,
+		State:           string(st.State),
+		Step:            int32(st.Step),
+		TrafficWeight:   int32(st.TrafficWeight),
+		StartedUnixMs:   st.StartedAt,
+		UpdatedUnixMs:   st.LastUpdated,
+		FinishedUnixMs:  st.FinishedAt,
+		Sequence:        st.Sequence,
+	}
+	if v := st.LastVerdict; v != nil {
+		r.LastVerdict = &rolloutpb.Verdict{
+			Verdict:       v.Verdict,
+			Decision:      v.Decision,
+			Reason:        v.Reason,
+			Metrics:       v.Metrics,
+			DecidedUnixMs: v.DecidedAt,
+		}
+	}
+	if o := st.Override; o != nil {
+		r.Override = &rolloutpb.Override{
+			Action:        string(o.Action),
+			Actor:         o.Actor,
+			Reason:        o.Reason,
+			SetUnixMs:     o.SetAt,
+			ExpiresUnixMs: o.ExpiresAt,
+		}
+	}
+	return r
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// putRollouts stores one rollout per state, of services a, b, c, ... in
+// tenant, labelled with its team.
+func putRollouts(t *testing.T, store storage.Store, tenant, team string, states ...storage.RolloutState) {
+	t.Helper()
+	for i, state := range states {
+		st := &storage.State{
+			RolloutID: tenant + "-" + string(rune('a'+i)),
+			Tenant:    tenant,
+			ServiceID: string(rune('a' + i)),
+			State:     state,
+			Labels:    map[string]string{"team": team},
+		}
+		if err := store.Save(context.Background(), st); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func rolloutIDs(resp *rolloutpb.ListRolloutsResponse) []string {
+	var ids []string
+	for _, r := range resp.Rollouts {
+		ids = append(ids, r.RolloutId)
+	}
+	return ids
+}
+
+func TestListRolloutsFilters(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestServer(t)
+	putRollouts(t, store, "acme", "payments", storage.Canary, storage.Paused, storage.RolledBack)
+	putRollouts(t, store, "globex", "search", storage.Canary, storage.Promoted)
+
+	cases := []struct {
+		name string
+		req  *rolloutpb.ListRolloutsRequest
+		want []string
+	}{
+		{"all", &rolloutpb.ListRolloutsRequest{}, []string{"acme-a", "acme-b", "acme-c", "globex-a", "globex-b"}},
+		{"tenant", &rolloutpb.ListRolloutsRequest{Tenant: "globex"}, []string{"globex-a", "globex-b"}},
+		{"states", &rolloutpb.ListRolloutsRequest{States: []string{"CANARY", "PAUSED"}}, []string{"acme-a", "acme-b", "globex-a"}},
+		{"labels", &rolloutpb.ListRolloutsRequest{Labels: map[string]string{"team": "search"}}, []string{"globex-a", "globex-b"}},
+		{
+			"combined",
+			&rolloutpb.ListRolloutsRequest{Tenant: "acme", States: []string{"ROLLED_BACK"}, Labels: map[string]string{"team": "payments"}},
+			[]string{"acme-c"},
+		},
+		{"no match", &rolloutpb.ListRolloutsRequest{Labels: map[string]string{"team": "ads"}}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resp, err := s.ListRollouts(ctx, c.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := rolloutIDs(resp)
+			if len(got) != len(c.want) {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Fatalf("expected %v, got %v", c.want, got)
+				}
+			}
+			if resp.NextPageToken != "" {
+				t.Fatalf("expected a single page, got next token %q", resp.NextPageToken)
+			}
+		})
+	}
+}
+
+func TestListRolloutsPages(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestServer(t)
+	putRollouts(t, store, "acme", "payments", storage.Canary, storage.Canary, storage.Canary, storage.Canary, storage.Canary)
+
+	var (
+		all   []string
+		token string
+	)
+	for pages := 1; ; pages++ {
+		resp, err := s.ListRollouts(ctx, &rolloutpb.ListRolloutsRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Rollouts) > 2 {
+			t.Fatalf("page %d has %d rollouts, expected at most 2", pages, len(resp.Rollouts))
+		}
+		all = append(all, rolloutIDs(resp)...)
+		if token = resp.NextPageToken; token == "" {
+			if pages != 3 {
+				t.Fatalf("expected 3 pages, got %d", pages)
+			}
+			break
+		}
+	}
+	if len(all) != 5 || all[0] != "acme-a" || all[4] != "acme-e" {
+		t.Fatalf("expected every rollout once in key order, got %v", all)
+	}
+}
+
+func TestListRolloutsRejectsUnknownStatesAndTenants(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestServer(t)
+
+	if _, err := s.ListRollouts(ctx, &rolloutpb.ListRolloutsRequest{States: []string{"RUNNING"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for an unknown state, got %v", err)
+	}
+	if _, err := s.ListRollouts(ctx, &rolloutpb.ListRolloutsRequest{Tenant: "a/b"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for an invalid tenant, got %v", err)
+	}
+}
+
+func TestGetRollout(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestServer(t)
+
+	_, err := s.GetRollout(ctx, &rolloutpb.GetRolloutRequest{ServiceId: "checkout-service"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound without a rollout, got %v", err)
+	}
+
+	putTestRollout(t, store, storage.Canary, 3)
+	resp, err := s.GetRollout(ctx, &rolloutpb.GetRolloutRequest{ServiceId: "checkout-service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := resp.Rollout; r.RolloutId != "r1" || r.Tenant != storage.DefaultTenant || r.State != string(storage.Canary) || r.Sequence != 3 {
+		t.Fatalf("unexpected rollout: %+v", r)
+	}
+}
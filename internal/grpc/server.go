@@ -1,36 +1,100 @@
 package grpc
 
 import (
+	"context"
 	"log"
 	"net"
 	"sync"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
 )
 
 type Server struct {
 	rolloutpb.UnimplementedRolloutControlServer
-	subscribers map[string][]chan *rolloutpb.DecisionEvent
+	store   storage.Store
+	machine *rollout.Machine
+	// subscribers are keyed by storage.Key, so a stream only sees its own
+	// tenant's decisions.
+	subscribers map[string][]*subscriber
 	mu          sync.Mutex
 }
 
-func NewServer() *Server {
+// subscriberBuffer is how many live decisions a stream may fall behind by
+// before it is closed.
+const subscriberBuffer = 10
+
+// subscriber queues live decisions for one StreamDecisions call.
+type subscriber struct {
+	events chan *rolloutpb.DecisionEvent
+	// overflow is closed, under Server.mu, when events was full: the
+	// decision that did not fit can only be recovered by resuming.
+	overflow chan struct{}
+}
+
+func NewServer(store storage.Store, machine *rollout.Machine) *Server {
 	return &Server{
-		subscribers: make(map[string][]chan *rolloutpb.DecisionEvent),
+		store:       store,
+		machine:     machine,
+		subscribers: make(map[string][]*subscriber),
 	}
 }
 
+// Publish sends event to StreamDecisions clients on every replica by way
+// of the store's decision bus. If the bus is unreachable, clients of this
+// replica still get it.
 func (s *Server) Publish(event *rolloutpb.DecisionEvent) {
+	bytes, err := proto.Marshal(event)
+	if err == nil {
+		err = s.store.PublishDecision(context.Background(), bytes)
+	}
+	if err != nil {
+		log.Printf("service=%s failed to fan out decision, delivering locally: %v",
+			storage.Key(event.Tenant, event.ServiceId), err)
+		s.deliver(event)
+	}
+}
+
+// Fanout subscribes to the decision bus and, until ctx is done, hands
+// every decision published by any replica to this replica's streams.
+func (s *Server) Fanout(ctx context.Context) error {
+	payloads, err := s.store.SubscribeDecisions(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for payload := range payloads {
+			var event rolloutpb.DecisionEvent
+			if err := proto.Unmarshal(payload, &event); err != nil {
+				log.Printf("invalid decision on the bus: %v", err)
+				continue
+			}
+			s.deliver(&event)
+		}
+	}()
+	return nil
+}
+
+// deliver sends event to the streams connected to this replica. A stream
+// too far behind to take it is closed, rather than left with a gap.
+func (s *Server) deliver(event *rolloutpb.DecisionEvent) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, ch := range s.subscribers[event.ServiceId] {
+	for _, sub := range s.subscribers[storage.Key(event.Tenant, event.ServiceId)] {
 		select {
-		case ch <- event:
+		case sub.events <- event:
+		case <-sub.overflow:
 		default:
-			log.Printf("dropping slow subscriber")
+			log.Printf("service=%s closing slow subscriber", storage.Key(event.Tenant, event.ServiceId))
+			close(sub.overflow)
 		}
 	}
 }
@@ -39,30 +103,77 @@ func (s *Server) StreamDecisions(
 	req *rolloutpb.StreamDecisionsRequest,
 	stream rolloutpb.RolloutControl_StreamDecisionsServer,
 ) error {
-	ch := make(chan *rolloutpb.DecisionEvent, 10)
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return err
+	}
+	sub := &subscriber{
+		events:   make(chan *rolloutpb.DecisionEvent, subscriberBuffer),
+		overflow: make(chan struct{}),
+	}
 
 	s.mu.Lock()
-	s.subscribers[req.ServiceId] = append(s.subscribers[req.ServiceId], ch)
+	s.subscribers[key] = append(s.subscribers[key], sub)
 	s.mu.Unlock()
 
 	defer func() {
 		s.mu.Lock()
-		subs := s.subscribers[req.ServiceId]
+		subs := s.subscribers[key]
 		for i, c := range subs {
-			if c == ch {
-				s.subscribers[req.ServiceId] = append(subs[:i], subs[i+1:]...)
+			if c == sub {
+				s.subscribers[key] = append(subs[:i], subs[i+1:]...)
 				break
 			}
 		}
 		s.mu.Unlock()
 	}()
 
-	for event := range ch {
-		if err := stream.Send(event); err != nil {
-			return err
+	// The stream is subscribed before the backfill reads the history, so
+	// a decision made meanwhile is either in the history or queued, and
+	// one that is in both is only sent once.
+	last, err := s.backfill(stream, key, req.FromSequence)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event := <-sub.events:
+			if event.Sequence != 0 && event.Sequence <= last {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+			if event.Sequence != 0 {
+				last = event.Sequence
+			}
+		case <-sub.overflow:
+			resume := last + 1
+			if last == 0 {
+				// Nothing has been sent: resume from the oldest queued
+				// decision.
+				select {
+				case event := <-sub.events:
+					resume = event.Sequence
+				default:
+				}
+			}
+			return status.Errorf(codes.ResourceExhausted,
+				"stream fell behind; reconnect with from_sequence %d", resume)
+		case <-stream.Context().Done():
+			return stream.Context().Err()
 		}
 	}
-	return nil
+}
+
+// storeKey validates a request's tenant and returns the store key of its
+// service.
+func storeKey(tenant, serviceID string) (string, error) {
+	if err := storage.ValidateTenant(tenant); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return storage.Key(tenant, serviceID), nil
 }
 
 func Run(server *Server) {
@@ -0,0 +1,204 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const testKey = "default/checkout-service"
+
+func newTestServer(t *testing.T) (*Server, storage.Store) {
+	t.Helper()
+	store := storage.NewMemory()
+	return NewServer(store, rollout.NewMachine()), store
+}
+
+// fakeStream hands every event sent on it to sent, blocking while sent
+// is full.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *rolloutpb.DecisionEvent
+}
+
+func newFakeStream(t *testing.T, buffer int) *fakeStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return &fakeStream{ctx: ctx, sent: make(chan *rolloutpb.DecisionEvent, buffer)}
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) Send(event *rolloutpb.DecisionEvent) error {
+	select {
+	case f.sent <- event:
+		return nil
+	case <-f.ctx.Done():
+		return f.ctx.Err()
+	}
+}
+
+func (f *fakeStream) next(t *testing.T) *rolloutpb.DecisionEvent {
+	t.Helper()
+	select {
+	case event := <-f.sent:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event sent")
+		return nil
+	}
+}
+
+// stream runs StreamDecisions for checkout-service in the background and
+// returns the channel its result arrives on.
+func stream(s *Server, f *fakeStream, from int64) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- s.StreamDecisions(&rolloutpb.StreamDecisionsRequest{
+			ServiceId:    "checkout-service",
+			FromSequence: from,
+		}, f)
+	}()
+	return done
+}
+
+// saveDecisions records a decision entry for each of entries' sequences
+// in the same save as the state, as the engine and overrides do.
+func saveDecisions(t *testing.T, store storage.Store, entries ...storage.HistoryEntry) {
+	t.Helper()
+	ctx := context.Background()
+	st, err := store.Get(ctx, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st == nil {
+		st = &storage.State{RolloutID: "r1", ServiceID: "checkout-service", State: storage.Canary}
+	}
+	for _, e := range entries {
+		e.RolloutID = st.RolloutID
+		e.ServiceID = "checkout-service"
+		st.Sequence = e.Sequence
+		st.History = []storage.HistoryEntry{e}
+		if err := store.Save(ctx, st); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStreamDecisionsBackfillsMissedDecisionsThenStreamsLiveOnes(t *testing.T) {
+	s, store := newTestServer(t)
+	saveDecisions(t, store,
+		storage.HistoryEntry{Kind: storage.HistoryDecision, Decision: "PROMOTE", DecisionID: "r1/w1", Sequence: 1},
+		storage.HistoryEntry{Kind: storage.HistoryManual, Decision: string(storage.OverridePause), Actor: "alice",
+			Reason: "investigating", DecisionID: "r1/manual/2", Sequence: 2},
+		storage.HistoryEntry{Kind: storage.HistoryDecision, Decision: "ROLLBACK", DecisionID: "r1/w3", Sequence: 3},
+	)
+
+	f := newFakeStream(t, 10)
+	done := stream(s, f, 2)
+
+	manual := f.next(t)
+	if manual.Sequence != 2 || manual.Source != rolloutpb.DecisionSource_MANUAL ||
+		manual.Decision != rolloutpb.DecisionType_PAUSE || manual.Actor != "alice" ||
+		manual.DecisionId != "r1/manual/2" || manual.RolloutId != "r1" || manual.Tenant != storage.DefaultTenant {
+		t.Fatalf("unexpected replay of the manual pause: %+v", manual)
+	}
+	automated := f.next(t)
+	if automated.Sequence != 3 || automated.Source != rolloutpb.DecisionSource_AUTOMATED ||
+		automated.Decision != rolloutpb.DecisionType_ROLLBACK {
+		t.Fatalf("unexpected replay of decision 3: %+v", automated)
+	}
+
+	// Decision 3 was already replayed from the history.
+	s.deliver(&rolloutpb.DecisionEvent{ServiceId: "checkout-service", Sequence: 3})
+	s.deliver(&rolloutpb.DecisionEvent{ServiceId: "checkout-service", Sequence: 4})
+	if live := f.next(t); live.Sequence != 4 {
+		t.Fatalf("expected live decision 4 after the backfill, got %d", live.Sequence)
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("stream ended early: %v", err)
+	default:
+	}
+}
+
+func TestStreamDecisionsFromTrimmedSequenceIsOutOfRange(t *testing.T) {
+	s, store := newTestServer(t)
+	store.SetHistoryRetention(storage.HistoryRetention{MaxLen: 2})
+	saveDecisions(t, store,
+		storage.HistoryEntry{Kind: storage.HistoryDecision, Sequence: 1},
+		storage.HistoryEntry{Kind: storage.HistoryDecision, Sequence: 2},
+		storage.HistoryEntry{Kind: storage.HistoryDecision, Sequence: 3},
+	)
+
+	err := <-stream(s, newFakeStream(t, 10), 1)
+	if status.Code(err) != codes.OutOfRange {
+		t.Fatalf("expected OutOfRange for a trimmed decision, got %v", err)
+	}
+}
+
+func TestStreamDecisionsClosesAStreamThatFallsBehind(t *testing.T) {
+	s, _ := newTestServer(t)
+	f := newFakeStream(t, 0)
+	done := stream(s, f, 0)
+
+	for {
+		s.mu.Lock()
+		subscribed := len(s.subscribers[testKey]) > 0
+		s.mu.Unlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The stream holds one decision in Send; the rest overflow its queue.
+	for seq := int64(1); seq <= subscriberBuffer+2; seq++ {
+		s.deliver(&rolloutpb.DecisionEvent{ServiceId: "checkout-service", Sequence: seq})
+	}
+
+	for {
+		select {
+		case <-f.sent:
+		case err := <-done:
+			if status.Code(err) != codes.ResourceExhausted {
+				t.Fatalf("expected ResourceExhausted for a lagging stream, got %v", err)
+			}
+			return
+		case <-time.After(time.Second):
+			t.Fatal("lagging stream was not closed")
+		}
+	}
+}
+
+func TestStreamDecisionsFromBeyondTheNextDecisionIsOutOfRange(t *testing.T) {
+	s, store := newTestServer(t)
+	saveDecisions(t, store,
+		storage.HistoryEntry{Kind: storage.HistoryDecision, Sequence: 1},
+		storage.HistoryEntry{Kind: storage.HistoryDecision, Sequence: 2},
+	)
+
+	err := <-stream(s, newFakeStream(t, 10), 4)
+	if status.Code(err) != codes.OutOfRange {
+		t.Fatalf("expected OutOfRange past the next decision, got %v", err)
+	}
+
+	// Resuming from the next decision waits for it.
+	f := newFakeStream(t, 10)
+	done := stream(s, f, 3)
+	select {
+	case err := <-done:
+		t.Fatalf("expected the stream to wait for decision 3, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
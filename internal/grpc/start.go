@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// StartRollout creates the rollout record in CANARY at the policy's first
+// step; the engine evaluates it from its next window on. A request that
+// finds a rollout in progress is not an error: it comes back unaccepted
+// with the reason and the ID of the rollout in the way.
+func (s *Server) StartRollout(
+	ctx context.Context,
+	req *rolloutpb.StartRolloutRequest,
+) (*rolloutpb.StartRolloutResponse, error) {
+	if req.ServiceId == "" || req.Version == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id and version are required")
+	}
+	key, err := storeKey(req.Tenant, req.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.loadPolicy(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *storage.State
+	st, err := storage.Update(ctx, s.store, key, func(cur *storage.State) (*storage.State, error) {
+		prev = cur
+		st, err := s.machine.Start(cur, policy, rollout.StartRequest{
+			Version:         req.Version,
+			BaselineVersion: req.BaselineVersion,
+			Labels:          req.Labels,
+			Supersede:       req.Supersede,
+		})
+		if err != nil {
+			return nil, err
+		}
+		e := storage.HistoryEntry{
+			RolloutID:     st.RolloutID,
+			Tenant:        policy.Tenant,
+			ServiceID:     req.ServiceId,
+			Kind:          storage.HistoryStart,
+			Actor:         req.Actor,
+			To:            st.State,
+			Reason:        fmt.Sprintf("version %q replacing %q", st.Version, st.BaselineVersion),
+			PolicyVersion: policy.Version,
+			TrafficWeight: st.TrafficWeight,
+		}
+		if cur != nil && !rollout.IsTerminal(cur.State) {
+			e.Comment = fmt.Sprintf("supersedes rollout %s in %s", cur.RolloutID, cur.State)
+		}
+		st.History = []storage.HistoryEntry{e}
+		return st, nil
+	})
+	switch {
+	case errors.Is(err, rollout.ErrRolloutActive):
+		return &rolloutpb.StartRolloutResponse{
+			RolloutId: prev.RolloutID,
+			RejectionReason: fmt.Sprintf("rollout %s of version %q is %s; set supersede to replace it",
+				prev.RolloutID, prev.Version, prev.State),
+			State:         string(prev.State),
+			TrafficWeight: int32(prev.TrafficWeight),
+		}, nil
+	case errors.Is(err, storage.ErrVersionConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &rolloutpb.StartRolloutResponse{
+		Accepted:      true,
+		RolloutId:     st.RolloutID,
+		State:         string(st.State),
+		TrafficWeight: int32(st.TrafficWeight),
+	}, nil
+}
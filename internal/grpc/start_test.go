@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rolloutpb "github.com/vineet4007/real-time-canary-control-plane/internal/grpc/rolloutpb"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/rollout"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+func startRequest(version string) *rolloutpb.StartRolloutRequest {
+	return &rolloutpb.StartRolloutRequest{
+		ServiceId:       "checkout-service",
+		Version:         version,
+		BaselineVersion: "v1",
+		Actor:           "alice",
+	}
+}
+
+func TestStartRolloutCreatesACanaryAtTheFirstStep(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestServer(t)
+	putTestPolicy(t, store)
+
+	resp, err := s.StartRollout(ctx, startRequest("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Accepted || resp.RolloutId == "" || resp.State != string(storage.Canary) || resp.TrafficWeight != 10 {
+		t.Fatalf("expected an accepted CANARY at 10%%, got %+v", resp)
+	}
+
+	st, err := store.Get(ctx, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st == nil || st.RolloutID != resp.RolloutId || st.Version != "v2" || st.BaselineVersion != "v1" {
+		t.Fatalf("expected the started rollout to be stored, got %+v", st)
+	}
+}
+
+func TestStartRolloutRejectsWithoutPolicyOrVersion(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestServer(t)
+
+	if _, err := s.StartRollout(ctx, startRequest("v2")); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound without a policy, got %v", err)
+	}
+	putTestPolicy(t, store)
+	if _, err := s.StartRollout(ctx, startRequest("")); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without a version, got %v", err)
+	}
+}
+
+func TestStartRolloutOverAnActiveRollout(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestServer(t)
+	putTestPolicy(t, store)
+
+	first, err := s.StartRollout(ctx, startRequest("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.StartRollout(ctx, startRequest("v3"))
+	if err != nil {
+		t.Fatalf("an active rollout must not be an error, got %v", err)
+	}
+	if resp.Accepted || resp.RolloutId != first.RolloutId || resp.State != string(storage.Canary) ||
+		!strings.Contains(resp.RejectionReason, first.RolloutId) || !strings.Contains(resp.RejectionReason, "supersede") {
+		t.Fatalf("expected an unaccepted response naming rollout %s, got %+v", first.RolloutId, resp)
+	}
+
+	req := startRequest("v3")
+	req.Supersede = true
+	resp, err = s.StartRollout(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Accepted || resp.RolloutId == first.RolloutId {
+		t.Fatalf("expected a new rollout to supersede %s, got %+v", first.RolloutId, resp)
+	}
+
+	entries, _, err := store.History(ctx, testKey, 0, 0, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected two START entries, got %d", len(entries))
+	}
+	if entries[0].Comment != "" {
+		t.Fatalf("the first rollout superseded nothing, got comment %q", entries[0].Comment)
+	}
+	want := "supersedes rollout " + first.RolloutId + " in CANARY"
+	if e := entries[1]; e.Kind != storage.HistoryStart || e.RolloutID != resp.RolloutId || e.Comment != want {
+		t.Fatalf("expected a START entry with comment %q, got %+v", want, e)
+	}
+}
+
+// noAppendStore refuses AppendHistory, so history only reaches it
+// through Save.
+type noAppendStore struct {
+	storage.Store
+}
+
+func (noAppendStore) AppendHistory(context.Context, *storage.HistoryEntry) error {
+	return errors.New("history must be written with the state")
+}
+
+func TestStartRolloutWritesItsHistoryWithTheState(t *testing.T) {
+	ctx := context.Background()
+	_, store := newTestServer(t)
+	putTestPolicy(t, store)
+	s := NewServer(noAppendStore{store}, rollout.NewMachine())
+
+	resp, err := s.StartRollout(ctx, startRequest("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err := store.History(ctx, testKey, 0, 0, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Kind != storage.HistoryStart || entries[0].RolloutID != resp.RolloutId {
+		t.Fatalf("expected the START entry, got %+v", entries)
+	}
+}
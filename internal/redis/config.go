@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Config selects and tunes the Redis deployment. With MasterName set
+// Addrs are Sentinel addresses; with Cluster set they are cluster seed
+// nodes; otherwise Addrs holds the single node.
+type Config struct {
+	Addrs      []string
+	MasterName string
+	Cluster    bool
+
+	// Username and Password authenticate as an ACL user (or with the
+	// legacy requirepass when Username is empty).
+	Username string
+	Password string
+	// SentinelUsername and SentinelPassword authenticate to Sentinel
+	// itself when it has its own ACL.
+	SentinelUsername string
+	SentinelPassword string
+	DB               int
+
+	TLS *TLSConfig
+
+	PoolSize     int
+	MinIdleConns int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+type TLSConfig struct {
+	// CAFile verifies the server; empty uses the system roots.
+	CAFile string
+	// CertFile and KeyFile present a client certificate.
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+func (c Config) options() (*goredis.UniversalOptions, error) {
+	if len(c.Addrs) == 0 {
+		return nil, fmt.Errorf("redis: no address configured")
+	}
+	if c.Cluster && c.MasterName != "" {
+		return nil, fmt.Errorf("redis: cluster mode and sentinel master are exclusive")
+	}
+	if len(c.Addrs) > 1 && !c.Cluster && c.MasterName == "" {
+		return nil, fmt.Errorf("redis: several addresses need cluster mode or a sentinel master name")
+	}
+	if c.Cluster && c.DB != 0 {
+		return nil, fmt.Errorf("redis: cluster mode only has database 0")
+	}
+
+	opts := &goredis.UniversalOptions{
+		Addrs:            c.Addrs,
+		MasterName:       c.MasterName,
+		IsClusterMode:    c.Cluster,
+		Username:         c.Username,
+		Password:         c.Password,
+		SentinelUsername: c.SentinelUsername,
+		SentinelPassword: c.SentinelPassword,
+		DB:               c.DB,
+		PoolSize:         c.PoolSize,
+		MinIdleConns:     c.MinIdleConns,
+		DialTimeout:      c.DialTimeout,
+		ReadTimeout:      c.ReadTimeout,
+		WriteTimeout:     c.WriteTimeout,
+	}
+
+	if c.TLS != nil {
+		tlsCfg, err := c.TLS.load()
+		if err != nil {
+			return nil, err
+		}
+		opts.TLSConfig = tlsCfg
+	}
+	return opts, nil
+}
+
+func (t *TLSConfig) load() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: t.ServerName,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("redis: read CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("redis: no certificates in %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("redis: load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
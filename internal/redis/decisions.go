@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// decisionChannel carries every published decision to every replica.
+// Under Cluster a PUBLISH reaches subscribers on all nodes.
+const decisionChannel = "rollout-decisions"
+
+func (s *Store) PublishDecision(ctx context.Context, payload []byte) error {
+	return s.client.Publish(ctx, decisionChannel, payload).Err()
+}
+
+func (s *Store) SubscribeDecisions(ctx context.Context) (<-chan []byte, error) {
+	return subscribe(ctx, s.client, decisionChannel, func(payload string) []byte {
+		return []byte(payload)
+	}, nil)
+}
+
+// subscribe relays channel's messages, converted by conv, until ctx is
+// done. It returns once the subscription is confirmed, so nothing
+// published after that is missed while connected. go-redis resubscribes
+// after a reconnect, but what was published in between is lost; a
+// non-nil resync is sent after every resubscription so the reader can
+// catch up.
+func subscribe[T any](
+	ctx context.Context,
+	client goredis.UniversalClient,
+	channel string,
+	conv func(payload string) T,
+	resync *T,
+) (<-chan T, error) {
+	sub := client.Subscribe(ctx, channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	out := make(chan T, 16)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		// The first confirmation was consumed above, so every one seen
+		// here follows a reconnect.
+		msgs := sub.ChannelWithSubscriptions()
+		for {
+			var (
+				v  T
+				ok bool
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case msg, open := <-msgs:
+				if !open {
+					return
+				}
+				switch msg := msg.(type) {
+				case *goredis.Message:
+					v, ok = conv(msg.Payload), true
+				case *goredis.Subscription:
+					if msg.Kind == "subscribe" && resync != nil {
+						v, ok = *resync, true
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
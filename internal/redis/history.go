@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// SetHistoryRetention applies r to every subsequent append.
+func (s *Store) SetHistoryRetention(r storage.HistoryRetention) {
+	s.retention = r
+}
+
+// trimArgs returns the MINID ("" for none) and MAXLEN (0 for none) that
+// trim a history stream to the retention as of now (unix ms).
+func (s *Store) trimArgs(now int64) (string, int64) {
+	var minID string
+	if s.retention.MaxAge > 0 {
+		minID = strconv.FormatInt(now-s.retention.MaxAge.Milliseconds(), 10)
+	}
+	return minID, s.retention.MaxLen
+}
+
+// AppendHistory adds e to the service's history stream and trims entries
+// that fall outside the retention.
+func (s *Store) AppendHistory(ctx context.Context, e *storage.HistoryEntry) error {
+	if e.Timestamp == 0 {
+		e.Timestamp = time.Now().UnixMilli()
+	}
+	bytes, _ := json.Marshal(e)
+
+	args := &goredis.XAddArgs{
+		Stream: historyKey(e.Key()),
+		Values: []string{"entry", string(bytes)},
+		Approx: true,
+	}
+	minID, maxLen := s.trimArgs(e.Timestamp)
+	if minID != "" {
+		args.MinID = minID
+	} else {
+		args.MaxLen = maxLen
+	}
+
+	id, err := s.client.XAdd(ctx, args).Result()
+	if err != nil {
+		return err
+	}
+	e.ID = id
+
+	// XADD takes a single trim strategy; apply the length cap separately
+	// when both are configured.
+	if minID != "" && maxLen > 0 {
+		return s.client.XTrimMaxLenApprox(ctx, historyKey(e.Key()), maxLen, 0).Err()
+	}
+	return nil
+}
+
+// History reads the service's stream; entry IDs are stream IDs, so the
+// range is on append time.
+func (s *Store) History(
+	ctx context.Context,
+	key string,
+	from, to int64,
+	after string,
+	limit int64,
+) (entries []*storage.HistoryEntry, next string, err error) {
+	start, end := "-", "+"
+	if from > 0 {
+		start = strconv.FormatInt(from, 10)
+	}
+	if after != "" {
+		if !validStreamID(after) {
+			return nil, "", storage.ErrInvalidPageToken
+		}
+		start = "(" + after
+	}
+	if to > 0 {
+		end = strconv.FormatInt(to, 10)
+	}
+
+	msgs, err := s.client.XRangeN(ctx, historyKey(key), start, end, limit+1).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, msg := range msgs {
+		if int64(len(entries)) == limit {
+			next = entries[len(entries)-1].ID
+			break
+		}
+		raw, _ := msg.Values["entry"].(string)
+		var e storage.HistoryEntry
+		if err := json.Unmarshal([]byte(raw), &e); err != nil {
+			return nil, "", err
+		}
+		e.ID = msg.ID
+		entries = append(entries, &e)
+	}
+	return entries, next, nil
+}
+
+// DecisionHistory looks the decisions up in the service's sequence index
+// and reads the stretch of the stream between the first and the last.
+func (s *Store) DecisionHistory(ctx context.Context, key string, from, limit int64) ([]*storage.HistoryEntry, error) {
+	index, err := s.client.ZRangeArgsWithScores(ctx, goredis.ZRangeArgs{
+		Key:     sequenceKey(key),
+		Start:   strconv.FormatInt(from, 10),
+		Stop:    "+inf",
+		ByScore: true,
+		Count:   limit,
+	}).Result()
+	if err != nil || len(index) == 0 {
+		return nil, err
+	}
+	if int64(index[0].Score) != from {
+		return nil, storage.ErrHistoryTrimmed
+	}
+
+	first, _ := index[0].Member.(string)
+	last, _ := index[len(index)-1].Member.(string)
+	msgs, err := s.client.XRange(ctx, historyKey(key), first, last).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 || msgs[0].ID != first {
+		return nil, storage.ErrHistoryTrimmed
+	}
+
+	var entries []*storage.HistoryEntry
+	for _, msg := range msgs {
+		raw, _ := msg.Values["entry"].(string)
+		var e storage.HistoryEntry
+		if err := json.Unmarshal([]byte(raw), &e); err != nil {
+			return nil, err
+		}
+		if e.Sequence < from {
+			continue
+		}
+		e.ID = msg.ID
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+
+func validStreamID(id string) bool {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return false
+	}
+	_, err1 := strconv.ParseUint(ms, 10, 64)
+	_, err2 := strconv.ParseUint(seq, 10, 64)
+	return err1 == nil && err2 == nil
+}
@@ -0,0 +1,82 @@
+package redis
+
+import "github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+
+// Every key carries a {hash tag} so that the keys one script touches land
+// in the same Cluster slot: per-service keys are tagged with the service,
+// policies share one slot so the index stays next to them, and a lease
+// shares its slot with the checkpoint fenced by it (both use the lease
+// name).
+//
+// Per-service keys take a storage.Key and start with its tenant, so a
+// tenant's data can be listed, scanned or granted in ACLs by prefix
+// (e.g. "~acme:*"). Leases and checkpoints belong to the engine, not a
+// tenant, and are not prefixed.
+
+// tenantKey builds "<tenant>:<kind>:{<key>}".
+func tenantKey(kind, key string) string {
+	tenant, _ := storage.SplitKey(key)
+	return tenant + ":" + kind + ":{" + key + "}"
+}
+
+func rolloutKey(key string) string {
+	return tenantKey("rollout", key)
+}
+
+// fenceKey holds the newest fencing token that wrote the service's state.
+func fenceKey(key string) string {
+	return tenantKey("fence", key)
+}
+
+func decisionKey(key, windowID string) string {
+	return tenantKey("decision", key) + ":" + windowID
+}
+
+// outboxKey is the service's stream of undelivered decisions; it shares
+// the rollout's slot so both are written by one script.
+func outboxKey(key string) string {
+	return tenantKey("outbox", key)
+}
+
+// rolloutIndexKey is the set of every tenant's rollout keys.
+func rolloutIndexKey() string {
+	return "rollouts:{rollouts}"
+}
+
+// outboxIndexKey is the set of services that have had an outbox.
+func outboxIndexKey() string {
+	return "outboxes:{outbox}"
+}
+
+func historyKey(key string) string {
+	return tenantKey("history", key)
+}
+
+// sequenceKey indexes the service's history by decision sequence: it is
+// a sorted set of history stream IDs scored by sequence number.
+func sequenceKey(key string) string {
+	return tenantKey("sequence", key)
+}
+
+func policyKey(key string) string {
+	tenant, service := storage.SplitKey(key)
+	return tenant + ":policy:{policies}:" + service
+}
+
+// policyIndexKey is the set of every tenant's policy keys, which the
+// engine reads on start.
+func policyIndexKey() string {
+	return "policies:{policies}"
+}
+
+func leaseKey(name string) string {
+	return "lease:{" + name + "}"
+}
+
+func leaseTokenKey(name string) string {
+	return "lease:{" + name + "}:token"
+}
+
+func checkpointKey(name string) string {
+	return "checkpoint:{" + name + "}"
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"strings"
+	"testing"
+)
+
+// hashTag returns the part of key Cluster hashes, per the spec.
+func hashTag(key string) string {
+	if s := strings.IndexByte(key, '{'); s >= 0 {
+		if e := strings.IndexByte(key[s+1:], '}'); e > 0 {
+			return key[s+1 : s+1+e]
+		}
+	}
+	return key
+}
+
+func TestScriptKeysShareASlot(t *testing.T) {
+	groups := map[string][]string{
+		"save state":  {rolloutKey("a/api"), fenceKey("a/api"), outboxKey("a/api"), historyKey("a/api"), sequenceKey("a/api")},
+		"put policy":  {policyKey("a/api"), policyKey("b/web"), policyIndexKey()},
+		"lease":       {leaseKey("decision-engine"), leaseTokenKey("decision-engine")},
+		"checkpoint":  {checkpointKey("decision-engine"), leaseKey("decision-engine")},
+		"per service": {rolloutKey("a/api"), decisionKey("a/api", "api/30s/0"), historyKey("a/api")},
+	}
+	for name, keys := range groups {
+		for _, k := range keys[1:] {
+			if hashTag(k) != hashTag(keys[0]) {
+				t.Errorf("%s: %s and %s hash to different slots", name, keys[0], k)
+			}
+		}
+	}
+
+	if hashTag(rolloutKey("a/api")) == hashTag(rolloutKey("a/web")) {
+		t.Error("different services should not be pinned to one slot")
+	}
+}
+
+func TestKeysArePrefixedWithTenant(t *testing.T) {
+	keys := []string{
+		rolloutKey("acme/api"),
+		fenceKey("acme/api"),
+		decisionKey("acme/api", "api/30s/0"),
+		historyKey("acme/api"),
+		outboxKey("acme/api"),
+		policyKey("acme/api"),
+	}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "acme:") {
+			t.Errorf("%s is not prefixed with its tenant", k)
+		}
+	}
+
+	if rolloutKey("acme/api") == rolloutKey("globex/api") {
+		t.Error("tenants share a rollout key")
+	}
+	if policyKey("acme/api") == policyKey("globex/api") {
+		t.Error("tenants share a policy key")
+	}
+}
+
+func TestLegacyServiceOnlyMatchesPreTenantKeys(t *testing.T) {
+	cases := []struct {
+		key, kind, want string
+	}{
+		{"rollout:checkout-service", "rollout", "checkout-service"},
+		{"rollout:{checkout-service}", "rollout", "checkout-service"},
+		{"history:{checkout-service}", "history", "checkout-service"},
+		{rolloutKey("default/checkout-service"), "rollout", ""},
+		{"rollout:{checkout-service", "rollout", ""},
+		{"rollouts:{rollouts}", "rollout", ""},
+		{"lease:{decision-engine}:token", "lease", ""},
+	}
+	for _, c := range cases {
+		got, ok := legacyService(c.key, c.kind)
+		if got != c.want || ok != (c.want != "") {
+			t.Errorf("legacyService(%q, %q) = %q, %v; want %q", c.key, c.kind, got, ok, c.want)
+		}
+	}
+
+	for key, want := range map[string]string{
+		"policy:checkout-service":             "checkout-service",
+		"policy:{policies}:checkout-service":  "checkout-service",
+		policyKey("default/checkout-service"): "",
+	} {
+		if got, ok := legacyPolicyService(key); got != want || ok != (want != "") {
+			t.Errorf("legacyPolicyService(%q) = %q, %v; want %q", key, got, ok, want)
+		}
+	}
+}
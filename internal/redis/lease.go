@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// acquireLeaseScript grants or renews the lease hash KEYS[1] for holder
+// ARGV[1] with a TTL of ARGV[2] ms. A new holder gets the next value of
+// the KEYS[2] counter as its fencing token. Returns the token, or 0.
+var acquireLeaseScript = goredis.NewScript(`
+local holder = redis.call('HGET', KEYS[1], 'holder')
+if holder == false then
+	local token = redis.call('INCR', KEYS[2])
+	redis.call('HSET', KEYS[1], 'holder', ARGV[1], 'token', token)
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return token
+end
+if holder == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return tonumber(redis.call('HGET', KEYS[1], 'token'))
+end
+return 0
+`)
+
+var releaseLeaseScript = goredis.NewScript(`
+if redis.call('HGET', KEYS[1], 'holder') == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+func (s *Store) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (int64, error) {
+	return acquireLeaseScript.Run(ctx, s.client,
+		[]string{leaseKey(name), leaseTokenKey(name)},
+		holder, ttl.Milliseconds(),
+	).Int64()
+}
+
+func (s *Store) ReleaseLease(ctx context.Context, name, holder string) error {
+	return releaseLeaseScript.Run(ctx, s.client, []string{leaseKey(name)}, holder).Err()
+}
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// listBatch is how many rollouts ListRollouts reads per round trip.
+const listBatch = 100
+
+// ListRollouts reads the rollout index in key order and fetches states a
+// batch at a time with pipelined GETs, which also works under Cluster
+// where a multi-key MGET would cross slots.
+func (s *Store) ListRollouts(
+	ctx context.Context,
+	f storage.RolloutFilter,
+	after string,
+	limit int64,
+) ([]*storage.State, string, error) {
+	if limit <= 0 {
+		return nil, "", nil
+	}
+	keys, err := s.client.SMembers(ctx, rolloutIndexKey()).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Strings(keys)
+	i := sort.SearchStrings(keys, after)
+	if i < len(keys) && keys[i] == after {
+		i++
+	}
+	keys = keys[i:]
+
+	var out []*storage.State
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > listBatch {
+			batch = batch[:listBatch]
+		}
+		keys = keys[len(batch):]
+
+		pipe := s.client.Pipeline()
+		cmds := make([]*goredis.StringCmd, len(batch))
+		for j, key := range batch {
+			cmds[j] = pipe.Get(ctx, rolloutKey(key))
+		}
+		if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+			return nil, "", err
+		}
+
+		for _, cmd := range cmds {
+			val, err := cmd.Result()
+			if err == goredis.Nil {
+				continue
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			var st storage.State
+			if err := json.Unmarshal([]byte(val), &st); err != nil {
+				return nil, "", err
+			}
+			if !f.Match(&st) {
+				continue
+			}
+			if int64(len(out)) == limit {
+				return out, out[len(out)-1].Key(), nil
+			}
+			out = append(out, &st)
+		}
+	}
+	return out, "", nil
+}
@@ -0,0 +1,379 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// Before tenants, per-service keys named the bare service, first as
+// "<kind>:<service>" and then, once hash-tagged, as "<kind>:{<service>}";
+// policies were "policy:<service>" and then "policy:{policies}:<service>",
+// indexed by service in "policies" and then "policies:{policies}".
+// MigrateLegacyKeys moves all of them to the default tenant.
+
+const (
+	migrationsKey       = "migrations:{migrations}"
+	migrationLockKey    = "migrations:{migrations}:lock"
+	tenantKeysMigration = "tenant-keys"
+
+	legacyPolicyIndexKey = "policies"
+	migrateBatch         = 500
+	migrationPoll        = 500 * time.Millisecond
+)
+
+// MigrateLegacyKeys moves rollouts, history, policies and checkpoints
+// written under the key layouts that predate tenants to the default
+// tenant's keys, and deletes the legacy fence and lease keys. It returns
+// how many keys it moved.
+//
+// The migration runs once per database: its completion is recorded and
+// later calls return at once. While another caller holds the migration
+// lock, MigrateLegacyKeys waits until that caller has recorded the
+// migration, or takes the lock over if it expires, so that no engine
+// writes tenant keys while legacy keys are still being moved. A legacy
+// key whose new key already holds a different value is left in place and
+// reported in the error; the migration is then retried on the next call.
+func (s *Store) MigrateLegacyKeys(ctx context.Context) (int, error) {
+	for {
+		done, err := s.client.SIsMember(ctx, migrationsKey, tenantKeysMigration).Result()
+		if err != nil || done {
+			return 0, err
+		}
+		locked, err := s.client.SetNX(ctx, migrationLockKey, 1, time.Minute).Result()
+		if err != nil {
+			return 0, err
+		}
+		if locked {
+			break
+		}
+		select {
+		case <-time.After(migrationPoll):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	defer s.client.Del(context.Background(), migrationLockKey)
+
+	m := &migration{Store: s}
+	for _, step := range []func(context.Context) error{
+		m.policies,
+		m.rollouts,
+		m.history,
+		m.checkpoints,
+		m.cleanup,
+	} {
+		if err := step(ctx); err != nil {
+			return m.moved, err
+		}
+	}
+	if len(m.conflicts) > 0 {
+		return m.moved, errors.Join(m.conflicts...)
+	}
+	return m.moved, s.client.SAdd(ctx, migrationsKey, tenantKeysMigration).Err()
+}
+
+type migration struct {
+	*Store
+	moved     int
+	conflicts []error
+}
+
+func (m *migration) policies(ctx context.Context) error {
+	keys, err := m.scan(ctx, "policy:*")
+	if err != nil {
+		return err
+	}
+	for _, legacy := range keys {
+		service, ok := legacyPolicyService(legacy)
+		if !ok {
+			continue
+		}
+		key := storage.Key(storage.DefaultTenant, service)
+		moved, err := m.move(ctx, legacy, policyKey(key), func(val []byte) ([]byte, error) {
+			var rec storage.PolicyRecord
+			if err := json.Unmarshal(val, &rec); err != nil {
+				return nil, err
+			}
+			rec.Tenant = storage.DefaultTenant
+			return json.Marshal(&rec)
+		})
+		if err != nil {
+			return err
+		}
+		if !moved {
+			continue
+		}
+		if err := m.client.SAdd(ctx, policyIndexKey(), key).Err(); err != nil {
+			return err
+		}
+		if err := m.client.SRem(ctx, policyIndexKey(), service).Err(); err != nil {
+			return err
+		}
+	}
+	return m.client.Del(ctx, legacyPolicyIndexKey).Err()
+}
+
+func (m *migration) rollouts(ctx context.Context) error {
+	keys, err := m.scan(ctx, "rollout:*")
+	if err != nil {
+		return err
+	}
+	for _, legacy := range keys {
+		service, ok := legacyService(legacy, "rollout")
+		if !ok {
+			continue
+		}
+		key := storage.Key(storage.DefaultTenant, service)
+		moved, err := m.move(ctx, legacy, rolloutKey(key), nil)
+		if err != nil {
+			return err
+		}
+		if moved {
+			if err := m.client.SAdd(ctx, rolloutIndexKey(), key).Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// move copies the string at from to the unused key to, converted by conv
+// if set, and deletes from. A key to that already holds the same value
+// counts as moved.
+func (m *migration) move(ctx context.Context, from, to string, conv func([]byte) ([]byte, error)) (bool, error) {
+	val, err := m.client.Get(ctx, from).Bytes()
+	if err == goredis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if conv != nil {
+		if val, err = conv(val); err != nil {
+			return false, fmt.Errorf("%s: %w", from, err)
+		}
+	}
+
+	set, err := m.client.SetNX(ctx, to, val, 0).Result()
+	if err != nil {
+		return false, err
+	}
+	if !set {
+		// A move cut short after the copy leaves the same value at to.
+		cur, err := m.client.Get(ctx, to).Bytes()
+		if err != nil && err != goredis.Nil {
+			return false, err
+		}
+		if bytes.Equal(cur, val) {
+			return true, m.client.Del(ctx, from).Err()
+		}
+		m.conflicts = append(m.conflicts, fmt.Errorf("%s already exists, legacy key %s left in place", to, from))
+		return false, nil
+	}
+	m.moved++
+	return true, m.client.Del(ctx, from).Err()
+}
+
+// history copies each legacy stream entry by entry, keeping the entry
+// IDs. A copy cut short is resumed after the last entry copied; a stream
+// that already has other entries is a conflict.
+func (m *migration) history(ctx context.Context) error {
+	keys, err := m.scan(ctx, "history:*")
+	if err != nil {
+		return err
+	}
+	for _, legacy := range keys {
+		service, ok := legacyService(legacy, "history")
+		if !ok {
+			continue
+		}
+		if err := m.copyStream(ctx, legacy, historyKey(storage.Key(storage.DefaultTenant, service))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *migration) copyStream(ctx context.Context, from, to string) error {
+	first, err := m.client.XRangeN(ctx, from, "-", "+", 1).Result()
+	if err != nil || len(first) == 0 {
+		return err
+	}
+
+	start := "-"
+	copied, err := m.client.XRangeN(ctx, to, "-", "+", 1).Result()
+	if err != nil {
+		return err
+	}
+	if len(copied) > 0 {
+		if copied[0].ID != first[0].ID {
+			m.conflicts = append(m.conflicts, fmt.Errorf("%s already has entries, legacy stream %s left in place", to, from))
+			return nil
+		}
+		last, err := m.client.XRevRangeN(ctx, to, "+", "-", 1).Result()
+		if err != nil {
+			return err
+		}
+		start = "(" + last[0].ID
+	}
+
+	for {
+		msgs, err := m.client.XRangeN(ctx, from, start, "+", migrateBatch).Result()
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			err := m.client.XAdd(ctx, &goredis.XAddArgs{Stream: to, ID: msg.ID, Values: msg.Values}).Err()
+			if err != nil {
+				return err
+			}
+		}
+		if len(msgs) < migrateBatch {
+			break
+		}
+		start = "(" + msgs[len(msgs)-1].ID
+	}
+	m.moved++
+	return m.client.Del(ctx, from).Err()
+}
+
+// checkpoints moves unhashed checkpoints to their hash-tagged key and
+// qualifies the services of the windows they hold with the default
+// tenant.
+func (m *migration) checkpoints(ctx context.Context) error {
+	keys, err := m.scan(ctx, "checkpoint:*")
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, "checkpoint:")
+		if strings.HasPrefix(name, "{") {
+			if err := m.qualifyCheckpoint(ctx, key); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := m.move(ctx, key, checkpointKey(name), qualifyWindows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *migration) qualifyCheckpoint(ctx context.Context, key string) error {
+	val, err := m.client.Get(ctx, key).Bytes()
+	if err == goredis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	qualified, err := qualifyWindows(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return m.client.SetXX(ctx, key, qualified, goredis.KeepTTL).Err()
+}
+
+func qualifyWindows(val []byte) ([]byte, error) {
+	var cp storage.Checkpoint
+	if err := json.Unmarshal(val, &cp); err != nil {
+		return nil, err
+	}
+	windows := make(map[string]json.RawMessage, len(cp.Windows))
+	for key, w := range cp.Windows {
+		if !strings.Contains(key, "/") {
+			key = storage.Key(storage.DefaultTenant, key)
+		}
+		windows[key] = w
+	}
+	cp.Windows = windows
+	return json.Marshal(&cp)
+}
+
+// cleanup deletes legacy fence keys, which only guarded legacy rollout
+// keys, and unhashed leases, which no engine reads any more.
+func (m *migration) cleanup(ctx context.Context) error {
+	for _, kind := range []string{"fence", "lease"} {
+		keys, err := m.scan(ctx, kind+":*")
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if kind == "lease" && strings.HasPrefix(key, "lease:{") {
+				continue
+			}
+			if kind == "fence" {
+				if _, ok := legacyService(key, kind); !ok {
+					continue
+				}
+			}
+			if err := m.client.Del(ctx, key).Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scan returns the keys matching pattern on every master.
+func (s *Store) scan(ctx context.Context, pattern string) ([]string, error) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	scanOne := func(ctx context.Context, c goredis.UniversalClient) error {
+		iter := c.Scan(ctx, 0, pattern, migrateBatch).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, iter.Val())
+			mu.Unlock()
+		}
+		return iter.Err()
+	}
+
+	if cluster, ok := s.client.(*goredis.ClusterClient); ok {
+		err := cluster.ForEachMaster(ctx, func(ctx context.Context, c *goredis.Client) error {
+			return scanOne(ctx, c)
+		})
+		return keys, err
+	}
+	return keys, scanOne(ctx, s.client)
+}
+
+// legacyService returns the service of a legacy "<kind>:<service>" or
+// "<kind>:{<service>}" key. Current keys start with their tenant and
+// carry "<tenant>/<service>" in the hash tag, so they never match.
+func legacyService(key, kind string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, kind+":")
+	if !ok {
+		return "", false
+	}
+	if inner, ok := strings.CutPrefix(rest, "{"); ok {
+		rest, ok = strings.CutSuffix(inner, "}")
+		if !ok {
+			return "", false
+		}
+	}
+	if rest == "" || strings.ContainsAny(rest, "{}/:") {
+		return "", false
+	}
+	return rest, true
+}
+
+func legacyPolicyService(key string) (string, bool) {
+	if service, ok := strings.CutPrefix(key, "policy:{policies}:"); ok {
+		return service, service != "" && !strings.ContainsAny(service, "{}/:")
+	}
+	return legacyService(key, "policy")
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+func (s *Store) OutboxKeys(ctx context.Context) ([]string, error) {
+	return s.client.SMembers(ctx, outboxIndexKey()).Result()
+}
+
+func (s *Store) PendingOutbox(ctx context.Context, key string, limit int64) ([]*storage.OutboxMessage, error) {
+	entries, err := s.client.XRangeN(ctx, outboxKey(key), "-", "+", limit).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]*storage.OutboxMessage, 0, len(entries))
+	for _, e := range entries {
+		id, _ := e.Values["decision_id"].(string)
+		payload, _ := e.Values["payload"].(string)
+		msgs = append(msgs, &storage.OutboxMessage{
+			ID:         e.ID,
+			DecisionID: id,
+			Payload:    []byte(payload),
+		})
+	}
+	return msgs, nil
+}
+
+// AckOutbox deletes the delivered entry. The service stays in the index;
+// an empty stream costs the relay one XRANGE per pass.
+func (s *Store) AckOutbox(ctx context.Context, key, id string) error {
+	return s.client.XDel(ctx, outboxKey(key), id).Err()
+}
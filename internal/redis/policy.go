@@ -0,0 +1,131 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+const policyUpdateChannel = "policy-updates"
+
+// putPolicyScript writes the record only if the stored version matches
+// ARGV[1] (0 = must not exist), then announces the change.
+var putPolicyScript = goredis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+local ver = 0
+if cur then ver = cjson.decode(cur).version end
+if ver ~= tonumber(ARGV[1]) then return 0 end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('SADD', KEYS[2], ARGV[3])
+redis.call('PUBLISH', ARGV[4], ARGV[3])
+return 1
+`)
+
+var deletePolicyScript = goredis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if not cur then return 0 end
+if cjson.decode(cur).version ~= tonumber(ARGV[1]) then return -1 end
+redis.call('DEL', KEYS[1])
+redis.call('SREM', KEYS[2], ARGV[2])
+redis.call('PUBLISH', ARGV[3], ARGV[2])
+return 1
+`)
+
+func (s *Store) PutPolicy(
+	ctx context.Context,
+	key string,
+	spec string,
+	expectedVersion int64,
+) (*storage.PolicyRecord, error) {
+	tenant, service := storage.SplitKey(key)
+	rec := &storage.PolicyRecord{
+		Tenant:    tenant,
+		ServiceID: service,
+		Version:   expectedVersion + 1,
+		Spec:      spec,
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+	bytes, _ := json.Marshal(rec)
+
+	ok, err := putPolicyScript.Run(ctx, s.client,
+		[]string{policyKey(key), policyIndexKey()},
+		expectedVersion, bytes, key, policyUpdateChannel,
+	).Int()
+	if err != nil {
+		return nil, err
+	}
+	if ok == 0 {
+		return nil, storage.ErrVersionConflict
+	}
+
+	return rec, nil
+}
+
+func (s *Store) GetPolicy(ctx context.Context, key string) (*storage.PolicyRecord, error) {
+	val, err := s.client.Get(ctx, policyKey(key)).Result()
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec storage.PolicyRecord
+	if err := json.Unmarshal([]byte(val), &rec); err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+func (s *Store) ListPolicies(ctx context.Context) ([]*storage.PolicyRecord, error) {
+	keys, err := s.client.SMembers(ctx, policyIndexKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	records := make([]*storage.PolicyRecord, 0, len(keys))
+	for _, key := range keys {
+		rec, err := s.GetPolicy(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			records = append(records, rec)
+		}
+	}
+
+	return records, nil
+}
+
+func (s *Store) DeletePolicy(
+	ctx context.Context,
+	key string,
+	expectedVersion int64,
+) (bool, error) {
+	res, err := deletePolicyScript.Run(ctx, s.client,
+		[]string{policyKey(key), policyIndexKey()},
+		expectedVersion, key, policyUpdateChannel,
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	if res < 0 {
+		return false, storage.ErrVersionConflict
+	}
+
+	return res == 1, nil
+}
+
+func (s *Store) SubscribePolicies(ctx context.Context) (<-chan string, error) {
+	resync := storage.ResyncPolicies
+	return subscribe(ctx, s.client, policyUpdateChannel, func(payload string) string {
+		return payload
+	}, &resync)
+}
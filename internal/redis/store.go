@@ -6,39 +6,39 @@ import (
 	"time"
 
 	goredis "github.com/redis/go-redis/v9"
-)
-
-type RolloutState string
 
-const (
-	Canary     RolloutState = "CANARY"
-	Promoted   RolloutState = "PROMOTED"
-	Paused     RolloutState = "PAUSED"
-	RolledBack RolloutState = "ROLLED_BACK"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
 )
 
-type State struct {
-	ServiceID    string       `json:"service_id"`
-	Version      string       `json:"version"`
-	State        RolloutState `json:"state"`
-	LastDecision string       `json:"last_decision"`
-	LastUpdated  int64        `json:"last_updated"`
-}
-
+// Store keeps rollout state, policies and history in Redis: a single
+// node, a Sentinel-managed master or a Cluster.
 type Store struct {
-	client *goredis.Client
+	client    goredis.UniversalClient
+	retention storage.HistoryRetention
 }
 
+var _ storage.Store = (*Store)(nil)
+
+// New connects to a single unauthenticated node.
 func New(addr string) *Store {
-	return &Store{
-		client: goredis.NewClient(&goredis.Options{
-			Addr: addr,
-		}),
+	s, _ := NewFromConfig(Config{Addrs: []string{addr}})
+	return s
+}
+
+func NewFromConfig(cfg Config) (*Store, error) {
+	opts, err := cfg.options()
+	if err != nil {
+		return nil, err
 	}
+	return &Store{client: goredis.NewUniversalClient(opts)}, nil
+}
+
+func (s *Store) Close() error {
+	return s.client.Close()
 }
 
-func (s *Store) Get(ctx context.Context, serviceID string) (*State, error) {
-	val, err := s.client.Get(ctx, rolloutKey(serviceID)).Result()
+func (s *Store) Get(ctx context.Context, key string) (*storage.State, error) {
+	val, err := s.client.Get(ctx, rolloutKey(key)).Result()
 	if err == goredis.Nil {
 		return nil, nil
 	}
@@ -46,7 +46,7 @@ func (s *Store) Get(ctx context.Context, serviceID string) (*State, error) {
 		return nil, err
 	}
 
-	var st State
+	var st storage.State
 	if err := json.Unmarshal([]byte(val), &st); err != nil {
 		return nil, err
 	}
@@ -54,22 +54,221 @@ func (s *Store) Get(ctx context.Context, serviceID string) (*State, error) {
 	return &st, nil
 }
 
-func (s *Store) Save(ctx context.Context, st *State) error {
-	st.LastUpdated = time.Now().UnixMilli()
-	bytes, _ := json.Marshal(st)
-	return s.client.Set(ctx, rolloutKey(st.ServiceID), bytes, 0).Err()
+// saveStateScript writes ARGV[2] only if the stored revision equals
+// ARGV[1] (0 = key must not exist). When ARGV[3] is a fencing token it
+// must be the token in the lease hash KEYS[6] and not older than the
+// newest token seen for the service (KEYS[2]). Under Cluster the lease
+// lives in another hash slot and is not passed; ARGV[7] is then the
+// lease's token as the caller read it just before.
+// ARGV[4] counts the (decision ID, payload) pairs from ARGV[8] on, which
+// are appended to the outbox stream KEYS[3] with the state. The rest of
+// ARGV are (entry, sequence) pairs appended to the history stream KEYS[4]
+// and trimmed like AppendHistory does (ARGV[5] is the MINID, if any, and
+// ARGV[6] the MAXLEN or 0). Entries with a sequence are indexed in
+// KEYS[5], which then drops the IDs the trim removed.
+// Returns {1, new}, {0, stored} on a conflict or {-1, 0} when fenced.
+var saveStateScript = goredis.NewScript(`
+local token = tonumber(ARGV[3])
+if token > 0 then
+	local lease = ARGV[7]
+	if KEYS[6] then lease = redis.call('HGET', KEYS[6], 'token') end
+	if lease ~= ARGV[3] or token < tonumber(redis.call('GET', KEYS[2]) or '0') then
+		return {-1, 0}
+	end
+end
+local cur = redis.call('GET', KEYS[1])
+local rev = 0
+if cur then rev = cjson.decode(cur).revision or 0 end
+if rev ~= tonumber(ARGV[1]) then return {0, rev} end
+redis.call('SET', KEYS[1], ARGV[2])
+if token > 0 then redis.call('SET', KEYS[2], token) end
+
+local history = 8 + 2 * tonumber(ARGV[4])
+for i = 8, history - 1, 2 do
+	redis.call('XADD', KEYS[3], '*', 'decision_id', ARGV[i], 'payload', ARGV[i + 1])
+end
+
+local indexed = false
+for i = history, #ARGV, 2 do
+	local id
+	if ARGV[5] ~= '' then
+		id = redis.call('XADD', KEYS[4], 'MINID', '~', ARGV[5], '*', 'entry', ARGV[i])
+		if ARGV[6] ~= '0' then redis.call('XTRIM', KEYS[4], 'MAXLEN', '~', ARGV[6]) end
+	elseif ARGV[6] ~= '0' then
+		id = redis.call('XADD', KEYS[4], 'MAXLEN', '~', ARGV[6], '*', 'entry', ARGV[i])
+	else
+		id = redis.call('XADD', KEYS[4], '*', 'entry', ARGV[i])
+	end
+	if ARGV[i + 1] ~= '0' then
+		redis.call('ZADD', KEYS[5], ARGV[i + 1], id)
+		indexed = true
+	end
+end
+
+if indexed then
+	local function before(a, b)
+		local ams, aseq = string.match(a, '(%d+)-(%d+)')
+		local bms, bseq = string.match(b, '(%d+)-(%d+)')
+		ams, aseq, bms, bseq = tonumber(ams), tonumber(aseq), tonumber(bms), tonumber(bseq)
+		return ams < bms or (ams == bms and aseq < bseq)
+	end
+	local oldest = redis.call('XRANGE', KEYS[4], '-', '+', 'COUNT', 1)[1][1]
+	while true do
+		local id = redis.call('ZRANGE', KEYS[5], 0, 0)[1]
+		if not id or not before(id, oldest) then break end
+		redis.call('ZREM', KEYS[5], id)
+	end
+end
+return {1, rev + 1}
+`)
+
+func (s *Store) Save(ctx context.Context, st *storage.State) error {
+	next := *st
+	next.Revision = st.Revision + 1
+	next.LastUpdated = time.Now().UnixMilli()
+	next.Outbox = nil
+	next.History = nil
+	bytes, _ := json.Marshal(&next)
+
+	// Like the outbox index below, the rollout index is in another slot
+	// and is added to ahead of the save. Every save re-adds, so rollouts
+	// written before the index existed show up once they next move.
+	if err := s.client.SAdd(ctx, rolloutIndexKey(), st.Key()).Err(); err != nil {
+		return err
+	}
+
+	keys := []string{
+		rolloutKey(st.Key()), fenceKey(st.Key()), outboxKey(st.Key()),
+		historyKey(st.Key()), sequenceKey(st.Key()),
+	}
+	fence, _ := storage.FenceFrom(ctx)
+	var leaseToken string
+	if fence.Token > 0 {
+		if _, ok := s.client.(*goredis.ClusterClient); ok {
+			var err error
+			leaseToken, err = s.client.HGet(ctx, leaseKey(fence.Lease), "token").Result()
+			if err == goredis.Nil {
+				return storage.ErrFenced
+			}
+			if err != nil {
+				return err
+			}
+		} else {
+			keys = append(keys, leaseKey(fence.Lease))
+		}
+	}
+	minID, maxLen := s.trimArgs(next.LastUpdated)
+	args := []interface{}{st.Revision, bytes, fence.Token, len(st.Outbox), minID, maxLen, leaseToken}
+	if len(st.Outbox) > 0 {
+		// The index lives in another slot, so it is added to first; an
+		// entry for a save that then fails is harmless.
+		if err := s.client.SAdd(ctx, outboxIndexKey(), st.Key()).Err(); err != nil {
+			return err
+		}
+		for _, msg := range st.Outbox {
+			args = append(args, msg.DecisionID, msg.Payload)
+		}
+	}
+	for i := range st.History {
+		e := &st.History[i]
+		if e.Timestamp == 0 {
+			e.Timestamp = next.LastUpdated
+		}
+		entry, _ := json.Marshal(e)
+		args = append(args, entry, e.Sequence)
+	}
+	res, err := saveStateScript.Run(ctx, s.client, keys, args...).Int64Slice()
+	if err != nil {
+		return err
+	}
+	switch res[0] {
+	case -1:
+		return storage.ErrFenced
+	case 0:
+		return &storage.ConflictError{Key: st.Key(), Expected: st.Revision, Actual: res[1]}
+	}
+
+	*st = next
+	return nil
 }
 
-func (s *Store) IdempotentDecision(
-	ctx context.Context,
-	serviceID string,
-	windowID string,
-) (bool, error) {
-	key := "decision:" + serviceID + ":" + windowID
-	ok, err := s.client.SetNX(ctx, key, "1", 2*time.Minute).Result()
-	return ok, err
+// fencedSetScript sets KEYS[1] to ARGV[1] unless ARGV[2] is a fencing
+// token other than the one in lease hash KEYS[2], which must share its
+// hash slot. Returns 0 when fenced.
+var fencedSetScript = goredis.NewScript(`
+if ARGV[2] ~= '0' and redis.call('HGET', KEYS[2], 'token') ~= ARGV[2] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1])
+return 1
+`)
+
+func (s *Store) SaveCheckpoint(ctx context.Context, name string, cp *storage.Checkpoint) error {
+	bytes, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	fence, _ := storage.FenceFrom(ctx)
+	ok, err := fencedSetScript.Run(ctx, s.client,
+		[]string{checkpointKey(name), leaseKey(fence.Lease)},
+		bytes, fence.Token,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return storage.ErrFenced
+	}
+	return nil
+}
+
+func (s *Store) LoadCheckpoint(ctx context.Context, name string) (*storage.Checkpoint, error) {
+	val, err := s.client.Get(ctx, checkpointKey(name)).Bytes()
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp storage.Checkpoint
+	if err := json.Unmarshal(val, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
 }
 
-func rolloutKey(serviceID string) string {
-	return "rollout:" + serviceID
+// claimWindowScript stores ARGV[1] with a TTL of ARGV[2] ms unless the
+// key is set, and returns the earlier value if there is one.
+var claimWindowScript = goredis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if cur then return cur end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false
+`)
+
+func (s *Store) ClaimWindow(
+	ctx context.Context,
+	key, windowID string,
+	d *storage.WindowDecision,
+	ttl time.Duration,
+) (*storage.WindowDecision, bool, error) {
+	bytes, _ := json.Marshal(d)
+	cur, err := claimWindowScript.Run(ctx, s.client,
+		[]string{decisionKey(key, windowID)},
+		bytes, ttl.Milliseconds(),
+	).Text()
+	if err == goredis.Nil {
+		return d, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var won storage.WindowDecision
+	if err := json.Unmarshal([]byte(cur), &won); err != nil {
+		return nil, false, err
+	}
+	return &won, false, nil
 }
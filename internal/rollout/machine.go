@@ -0,0 +1,89 @@
+package rollout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// transitions lists the legal moves out of each state. Self-transitions
+// are included where a state may be re-entered (e.g. another paused
+// window). PROMOTED and ROLLED_BACK have no way out.
+var transitions = map[storage.RolloutState][]storage.RolloutState{
+	storage.Canary: {
+		storage.Canary, storage.Paused, storage.AwaitingApproval, storage.Promoted, storage.RolledBack,
+	},
+	storage.Paused: {
+		storage.Paused, storage.Canary, storage.AwaitingApproval, storage.Promoted, storage.RolledBack,
+	},
+	// An operator may pause a rollout waiting at an approval gate.
+	storage.AwaitingApproval: {
+		storage.AwaitingApproval, storage.Canary, storage.Paused, storage.Promoted, storage.RolledBack,
+	},
+	storage.Promoted:   nil,
+	storage.RolledBack: nil,
+}
+
+// IsKnown reports whether s is a state of the machine.
+func IsKnown(s storage.RolloutState) bool {
+	_, known := transitions[s]
+	return known
+}
+
+// IsTerminal reports whether a rollout in state s is finished.
+func IsTerminal(s storage.RolloutState) bool {
+	next, known := transitions[s]
+	return known && len(next) == 0
+}
+
+func CanTransition(from, to storage.RolloutState) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError reports an attempt to move a rollout along an edge
+// the state machine does not have.
+type TransitionError struct {
+	ServiceID string
+	From, To  storage.RolloutState
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("service %s: illegal rollout transition %s -> %s", e.ServiceID, e.From, e.To)
+}
+
+// Hook observes a transition that has been applied to st. from == to for
+// self-transitions.
+type Hook func(st *storage.State, from, to storage.RolloutState)
+
+// Machine enforces the legal transitions and runs hooks on every change.
+type Machine struct {
+	hooks []Hook
+}
+
+func NewMachine(hooks ...Hook) *Machine {
+	return &Machine{hooks: hooks}
+}
+
+// Transition moves st to the given state, stamping FinishedAt on entering
+// a terminal one, or returns a *TransitionError leaving st untouched.
+func (m *Machine) Transition(st *storage.State, to storage.RolloutState) error {
+	from := st.State
+	if !CanTransition(from, to) {
+		return &TransitionError{ServiceID: st.ServiceID, From: from, To: to}
+	}
+
+	st.State = to
+	if IsTerminal(to) && st.FinishedAt == 0 {
+		st.FinishedAt = time.Now().UnixMilli()
+	}
+	for _, h := range m.hooks {
+		h(st, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package rollout
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+var ErrNotPaused = errors.New("rollout is not paused")
+
+// Override applies an operator's action to st. Pause holds the rollout
+// in PAUSED and stays recorded on it, so automated verdicts are ignored
+// while it is in force (see Held); every other action clears it. Abort
+// rolls back and Promote finishes the rollout at full weight, whatever
+// the windows said. Resume returns the rollout to CANARY, so one paused
+// at an approval gate is held there again by its next healthy window.
+func (m *Machine) Override(st *storage.State, policy *decision.Policy, o storage.Override) error {
+	switch o.Action {
+	case storage.OverridePause:
+		if err := m.Transition(st, storage.Paused); err != nil {
+			return err
+		}
+		st.LastDecision = string(o.Action)
+		st.Override = &o
+		return nil
+
+	case storage.OverrideResume:
+		if st.State != storage.Paused {
+			return ErrNotPaused
+		}
+		if err := m.Transition(st, storage.Canary); err != nil {
+			return err
+		}
+
+	case storage.OverrideAbort:
+		if err := m.Transition(st, storage.RolledBack); err != nil {
+			return err
+		}
+		st.TrafficWeight = 0
+
+	case storage.OverridePromote:
+		if err := m.Transition(st, storage.Promoted); err != nil {
+			return err
+		}
+		if len(policy.Steps) > 0 {
+			st.Step = len(policy.Steps) - 1
+		}
+		st.TrafficWeight = 100
+
+	default:
+		return fmt.Errorf("unknown override %q", o.Action)
+	}
+	st.LastDecision = string(o.Action)
+	st.Override = nil
+	return nil
+}
+
+// Held reports whether a manual pause holds st at now, in which case
+// automated verdicts must leave it alone.
+func Held(st *storage.State, now time.Time) bool {
+	o := st.Override
+	return o != nil && o.Action == storage.OverridePause &&
+		(o.ExpiresAt == 0 || now.UnixMilli() < o.ExpiresAt)
+}
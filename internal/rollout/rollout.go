@@ -0,0 +1,135 @@
+// Package rollout applies window verdicts and human approvals to a
+// rollout's persisted state.
+package rollout
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+var (
+	ErrNotAwaitingApproval = errors.New("rollout is not awaiting approval")
+	ErrDuplicateApprover   = errors.New("approver has already approved this step")
+)
+
+// Advance applies a window verdict to prev (nil starts a rollout of an
+// unknown version) and returns the next state together with the decision
+// actually taken,
+// which differs from the verdict when an approval gate holds the rollout.
+// prev is never modified; an illegal move returns a *TransitionError.
+func (m *Machine) Advance(
+	prev *storage.State,
+	policy *decision.Policy,
+	v decision.Verdict,
+) (*storage.State, decision.DecisionType, string, error) {
+	st := start(policy, StartRequest{})
+	if prev != nil {
+		cp := *prev
+		st = &cp
+	}
+	st.LastDecision = string(v.Decision)
+
+	switch v.Decision {
+	case decision.Rollback:
+		if err := m.Transition(st, storage.RolledBack); err != nil {
+			return nil, "", "", err
+		}
+		st.TrafficWeight = 0
+		return st, decision.Rollback, v.Reason, nil
+
+	case decision.Pause:
+		if st.State == storage.AwaitingApproval {
+			if err := m.Transition(st, storage.AwaitingApproval); err != nil {
+				return nil, "", "", err
+			}
+			return st, decision.Pause, "awaiting approval; " + v.Reason, nil
+		}
+		if err := m.Transition(st, storage.Paused); err != nil {
+			return nil, "", "", err
+		}
+		return st, decision.Pause, v.Reason, nil
+	}
+
+	if len(policy.Steps) == 0 {
+		if err := m.Transition(st, storage.Promoted); err != nil {
+			return nil, "", "", err
+		}
+		st.TrafficWeight = 100
+		return st, decision.Promote, v.Reason, nil
+	}
+
+	if st.Step >= len(policy.Steps) {
+		st.Step = len(policy.Steps) - 1
+	}
+
+	if gate := policy.Steps[st.Step].Approval; gate != nil {
+		got := ApprovalsAt(st, st.Step)
+		if got < gate.RequiredApprovers {
+			if err := m.Transition(st, storage.AwaitingApproval); err != nil {
+				return nil, "", "", err
+			}
+			return st, decision.Pause, fmt.Sprintf("awaiting approval at %d%% (%d/%d approvers)",
+				st.TrafficWeight, got, gate.RequiredApprovers), nil
+		}
+	}
+
+	next := storage.Canary
+	if st.Step+1 < len(policy.Steps) {
+		st.Step++
+	}
+	if st.Step == len(policy.Steps)-1 {
+		next = storage.Promoted
+	}
+	if err := m.Transition(st, next); err != nil {
+		return nil, "", "", err
+	}
+	st.TrafficWeight = policy.Steps[st.Step].Weight
+
+	return st, decision.Promote, fmt.Sprintf("%s; traffic weight %d%%", v.Reason, st.TrafficWeight), nil
+}
+
+// Approve records approver's sign-off on the gate the rollout is waiting
+// at. Once the step has enough distinct approvers the rollout returns to
+// CANARY and advances on its next healthy window.
+func (m *Machine) Approve(st *storage.State, policy *decision.Policy, approver, comment string) (required int, err error) {
+	if st.State != storage.AwaitingApproval || st.Step >= len(policy.Steps) ||
+		policy.Steps[st.Step].Approval == nil {
+		return 0, ErrNotAwaitingApproval
+	}
+	required = policy.Steps[st.Step].Approval.RequiredApprovers
+
+	for _, a := range st.Approvals {
+		if a.Step == st.Step && a.Approver == approver {
+			return required, ErrDuplicateApprover
+		}
+	}
+
+	st.Approvals = append(st.Approvals, storage.Approval{
+		Approver:   approver,
+		Step:       st.Step,
+		Comment:    comment,
+		ApprovedAt: time.Now().UnixMilli(),
+	})
+
+	if ApprovalsAt(st, st.Step) >= required {
+		if err := m.Transition(st, storage.Canary); err != nil {
+			return required, err
+		}
+	}
+	return required, nil
+}
+
+// ApprovalsAt counts distinct approvals recorded for step.
+func ApprovalsAt(st *storage.State, step int) int {
+	n := 0
+	for _, a := range st.Approvals {
+		if a.Step == step {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,221 @@
+package rollout
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+func gatedPolicy() *decision.Policy {
+	return &decision.Policy{
+		Service:       "checkout-service",
+		WindowSeconds: 30,
+		Steps: []decision.Step{
+			{Weight: 10},
+			{Weight: 50, Approval: &decision.Approval{RequiredApprovers: 2}},
+			{Weight: 100},
+		},
+	}
+}
+
+var (
+	healthy  = decision.Verdict{Decision: decision.Promote, Reason: "healthy"}
+	degraded = decision.Verdict{Decision: decision.Rollback, Reason: "error rate"}
+)
+
+func TestApprovalGateHoldsUntilDistinctApprovers(t *testing.T) {
+	policy := gatedPolicy()
+	m := NewMachine()
+
+	st, d, _, _ := m.Advance(nil, policy, healthy)
+	if st.TrafficWeight != 50 || st.State != storage.Canary || d != decision.Promote {
+		t.Fatalf("expected CANARY at 50%%, got %s at %d%% (%s)", st.State, st.TrafficWeight, d)
+	}
+
+	st, d, _, _ = m.Advance(st, policy, healthy)
+	if st.State != storage.AwaitingApproval || st.TrafficWeight != 50 || d != decision.Pause {
+		t.Fatalf("expected AWAITING_APPROVAL at 50%%, got %s at %d%% (%s)", st.State, st.TrafficWeight, d)
+	}
+
+	if _, err := m.Approve(st, policy, "alice", "looks good"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Approve(st, policy, "alice", "again"); !errors.Is(err, ErrDuplicateApprover) {
+		t.Fatalf("expected duplicate approver error, got %v", err)
+	}
+	if st.State != storage.AwaitingApproval {
+		t.Fatalf("one approval must not release the gate, got %s", st.State)
+	}
+
+	if _, err := m.Approve(st, policy, "bob", ""); err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.Canary {
+		t.Fatalf("expected CANARY after two approvals, got %s", st.State)
+	}
+
+	st, d, _, _ = m.Advance(st, policy, healthy)
+	if st.State != storage.Promoted || st.TrafficWeight != 100 || d != decision.Promote {
+		t.Fatalf("expected PROMOTED at 100%%, got %s at %d%% (%s)", st.State, st.TrafficWeight, d)
+	}
+	if len(st.Approvals) != 2 || st.Approvals[1].Approver != "bob" || st.Approvals[1].ApprovedAt == 0 {
+		t.Fatalf("approvals not recorded: %+v", st.Approvals)
+	}
+}
+
+func TestRollbackWhileAwaitingApproval(t *testing.T) {
+	policy := gatedPolicy()
+	m := NewMachine()
+
+	st, _, _, _ := m.Advance(nil, policy, healthy)
+	st, _, _, _ = m.Advance(st, policy, healthy)
+	if st.State != storage.AwaitingApproval {
+		t.Fatalf("expected AWAITING_APPROVAL, got %s", st.State)
+	}
+
+	st, d, _, _ := m.Advance(st, policy, degraded)
+	if st.State != storage.RolledBack || d != decision.Rollback {
+		t.Fatalf("expected ROLLED_BACK, got %s (%s)", st.State, d)
+	}
+	if _, err := m.Approve(st, policy, "alice", ""); !errors.Is(err, ErrNotAwaitingApproval) {
+		t.Fatalf("expected not-awaiting error, got %v", err)
+	}
+}
+
+func TestTerminalStatesRejectTransitions(t *testing.T) {
+	var seen []storage.RolloutState
+	m := NewMachine(func(st *storage.State, from, to storage.RolloutState) {
+		seen = append(seen, to)
+	})
+	policy := &decision.Policy{Service: "checkout-service", WindowSeconds: 30}
+
+	st, _, _, err := m.Advance(nil, policy, degraded)
+	if err != nil || st.State != storage.RolledBack {
+		t.Fatalf("expected ROLLED_BACK, got %v, %v", st, err)
+	}
+	if !IsTerminal(st.State) {
+		t.Fatal("ROLLED_BACK must be terminal")
+	}
+	if st.FinishedAt == 0 {
+		t.Fatal("entering a terminal state must stamp FinishedAt")
+	}
+
+	_, _, _, err = m.Advance(st, policy, healthy)
+	var terr *TransitionError
+	if !errors.As(err, &terr) || terr.From != storage.RolledBack || terr.To != storage.Promoted {
+		t.Fatalf("expected ROLLED_BACK -> PROMOTED to be rejected, got %v", err)
+	}
+	if st.State != storage.RolledBack {
+		t.Fatalf("rejected transition must not modify state, got %s", st.State)
+	}
+
+	if len(seen) != 1 || seen[0] != storage.RolledBack {
+		t.Fatalf("hooks should see exactly the applied transition, got %v", seen)
+	}
+}
+
+func TestStartRejectsActiveRolloutUnlessSuperseding(t *testing.T) {
+	policy := gatedPolicy()
+	m := NewMachine()
+
+	first, err := m.Start(nil, policy, StartRequest{Version: "v2", BaselineVersion: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.RolloutID == "" || first.StartedAt == 0 || first.Version != "v2" ||
+		first.BaselineVersion != "v1" || first.State != storage.Canary || first.TrafficWeight != 10 {
+		t.Fatalf("unexpected new rollout: %+v", first)
+	}
+
+	if _, err := m.Start(first, policy, StartRequest{Version: "v3"}); !errors.Is(err, ErrRolloutActive) {
+		t.Fatalf("expected an active rollout to be rejected, got %v", err)
+	}
+
+	first.Sequence = 3
+	second, err := m.Start(first, policy, StartRequest{Version: "v3", Supersede: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RolloutID == first.RolloutID || second.Version != "v3" || first.Version != "v2" {
+		t.Fatalf("supersede must start a new rollout and leave the old one alone: %+v, %+v", first, second)
+	}
+	if second.Sequence != 3 {
+		t.Fatalf("decision sequence must carry over to the next rollout, got %d", second.Sequence)
+	}
+
+	done, _, _, _ := m.Advance(second, policy, degraded)
+	if _, err := m.Start(done, policy, StartRequest{Version: "v4"}); err != nil {
+		t.Fatalf("a finished rollout must not block the next one: %v", err)
+	}
+}
+
+func TestManualPauseHoldsUntilExpiryOrResume(t *testing.T) {
+	policy := gatedPolicy()
+	m := NewMachine()
+	now := time.Now()
+
+	st, err := m.Start(nil, policy, StartRequest{Version: "v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Override(st, policy, storage.Override{Action: storage.OverrideResume, Actor: "oncall"}); !errors.Is(err, ErrNotPaused) {
+		t.Fatalf("expected resume of a running rollout to fail, got %v", err)
+	}
+
+	pause := storage.Override{Action: storage.OverridePause, Actor: "oncall", ExpiresAt: now.Add(time.Minute).UnixMilli()}
+	if err := m.Override(st, policy, pause); err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.Paused || !Held(st, now) {
+		t.Fatalf("expected a held pause, got %+v", st)
+	}
+	if Held(st, now.Add(2*time.Minute)) {
+		t.Fatal("an expired pause must not hold the rollout")
+	}
+
+	if err := m.Override(st, policy, storage.Override{Action: storage.OverrideResume, Actor: "oncall"}); err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.Canary || st.Override != nil || Held(st, now) {
+		t.Fatalf("expected resume to clear the pause, got %+v", st)
+	}
+
+	if err := m.Override(st, policy, storage.Override{Action: storage.OverridePromote, Actor: "oncall"}); err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.Promoted || st.TrafficWeight != 100 || st.Step != len(policy.Steps)-1 {
+		t.Fatalf("expected a forced promotion at full weight, got %+v", st)
+	}
+	if err := m.Override(st, policy, storage.Override{Action: storage.OverrideAbort, Actor: "oncall"}); !errors.As(err, new(*TransitionError)) {
+		t.Fatalf("expected abort of a finished rollout to fail, got %v", err)
+	}
+}
+
+func TestPauseAndResumeAtAnApprovalGate(t *testing.T) {
+	policy := gatedPolicy()
+	m := NewMachine()
+
+	st, _, _, _ := m.Advance(nil, policy, healthy)
+	st, _, _, _ = m.Advance(st, policy, healthy)
+	if st.State != storage.AwaitingApproval {
+		t.Fatalf("expected AWAITING_APPROVAL, got %s", st.State)
+	}
+
+	if err := m.Override(st, policy, storage.Override{Action: storage.OverridePause, Actor: "oncall"}); err != nil {
+		t.Fatal(err)
+	}
+	if st.State != storage.Paused {
+		t.Fatalf("expected PAUSED, got %s", st.State)
+	}
+	if err := m.Override(st, policy, storage.Override{Action: storage.OverrideResume, Actor: "oncall"}); err != nil {
+		t.Fatal(err)
+	}
+
+	st, d, _, _ := m.Advance(st, policy, healthy)
+	if st.State != storage.AwaitingApproval || d != decision.Pause || st.TrafficWeight != 50 {
+		t.Fatalf("expected the gate to hold again at 50%%, got %s (%s) at %d%%", st.State, d, st.TrafficWeight)
+	}
+}
@@ -0,0 +1,67 @@
+package rollout
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/vineet4007/real-time-canary-control-plane/internal/decision"
+	"github.com/vineet4007/real-time-canary-control-plane/internal/storage"
+)
+
+// ErrRolloutActive is returned by Start while the service's current
+// rollout is still in progress.
+var ErrRolloutActive = errors.New("a rollout is already in progress")
+
+// StartRequest describes a new rollout of a service.
+type StartRequest struct {
+	// Version is being rolled out; BaselineVersion is what it replaces.
+	Version         string
+	BaselineVersion string
+	// Labels are free-form tags for ListRollouts to filter on.
+	Labels map[string]string
+	// Supersede replaces a rollout that is still in progress instead of
+	// failing with ErrRolloutActive.
+	Supersede bool
+}
+
+// Start begins a new rollout at the policy's first step. A service runs
+// one rollout at a time: a finished rollout is simply replaced, while one
+// still in progress is only replaced when req.Supersede is set. Rollouts
+// are not queued; the caller retries once the current one finishes.
+// cur (nil if the service never had a rollout) is never modified.
+func (m *Machine) Start(cur *storage.State, policy *decision.Policy, req StartRequest) (*storage.State, error) {
+	if cur != nil && !IsTerminal(cur.State) && !req.Supersede {
+		return nil, ErrRolloutActive
+	}
+	st := start(policy, req)
+	if cur != nil {
+		st.Sequence = cur.Sequence
+	}
+	return st, nil
+}
+
+func start(policy *decision.Policy, req StartRequest) *storage.State {
+	st := &storage.State{
+		RolloutID:       newRolloutID(),
+		Tenant:          policy.Tenant,
+		ServiceID:       policy.Service,
+		Version:         req.Version,
+		BaselineVersion: req.BaselineVersion,
+		Labels:          req.Labels,
+		State:           storage.Canary,
+		StartedAt:       time.Now().UnixMilli(),
+	}
+	if len(policy.Steps) > 0 {
+		st.TrafficWeight = policy.Steps[0].Weight
+	}
+	return st
+}
+
+// newRolloutID returns a random 128-bit ID in hex.
+func newRolloutID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
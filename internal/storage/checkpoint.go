@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Checkpoint is the evaluator's in-flight progress: the open window of
+// every service and the Kafka offsets those windows are complete up to.
+type Checkpoint struct {
+	// Offsets is the next offset to consume, per partition.
+	Offsets map[int]int64 `json:"offsets"`
+	// Windows holds each service's open window as encoded by the engine.
+	Windows map[string]json.RawMessage `json:"windows"`
+	SavedAt int64                      `json:"saved_at"`
+}
+
+// CheckpointStore keeps the latest checkpoint under a name. Saves honour
+// a Fence in ctx like Save does.
+type CheckpointStore interface {
+	SaveCheckpoint(ctx context.Context, name string, cp *Checkpoint) error
+	// LoadCheckpoint returns nil, nil when nothing was saved.
+	LoadCheckpoint(ctx context.Context, name string) (*Checkpoint, error)
+}
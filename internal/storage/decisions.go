@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+// DecisionBus fans published decisions out to every process subscribed
+// to it, so each gRPC replica can serve every decision no matter which
+// replica made it. Delivery is best effort: a subscriber that is not
+// connected, or too far behind, misses messages.
+type DecisionBus interface {
+	PublishDecision(ctx context.Context, payload []byte) error
+	// SubscribeDecisions is subscribed once it returns. The channel is
+	// closed when ctx is done.
+	SubscribeDecisions(ctx context.Context) (<-chan []byte, error)
+}
+
+// broadcastBuffer is how far a Broadcaster subscriber may fall behind.
+const broadcastBuffer = 256
+
+// Broadcaster is an in-process DecisionBus for backends that only ever
+// serve a single process. Unlike Notifier it does not coalesce, and a
+// subscriber more than broadcastBuffer messages behind drops the excess.
+type Broadcaster struct {
+	mu   sync.Mutex
+	subs map[chan []byte]struct{}
+}
+
+func (b *Broadcaster) Subscribe(ctx context.Context) <-chan []byte {
+	ch := make(chan []byte, broadcastBuffer)
+
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[chan []byte]struct{})
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+	return ch
+}
+
+func (b *Broadcaster) Broadcast(payload []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- payload:
+		default:
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFenced is returned by a fenced write whose token is no longer the
+// lease's current one: the writer lost leadership and must stop.
+var ErrFenced = errors.New("fencing token is stale")
+
+// LeaseStore grants named, expiring leases for leader election. Each
+// grant to a new holder comes with a fencing token larger than any
+// issued before for that lease.
+type LeaseStore interface {
+	// AcquireLease takes the lease for holder, or renews it if holder
+	// already has it, for ttl. It returns the fencing token, or 0 if
+	// someone else holds the lease.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (int64, error)
+	// ReleaseLease gives the lease up early if holder has it.
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// Fence identifies the lease a write is made under.
+type Fence struct {
+	Lease string
+	Token int64
+}
+
+type fenceKey struct{}
+
+// WithFence marks writes made with ctx as fenced: Save fails with
+// ErrFenced unless token is still the current token of lease.
+func WithFence(ctx context.Context, lease string, token int64) context.Context {
+	return context.WithValue(ctx, fenceKey{}, Fence{Lease: lease, Token: token})
+}
+
+func FenceFrom(ctx context.Context) (Fence, bool) {
+	f, ok := ctx.Value(fenceKey{}).(Fence)
+	return f, ok
+}
@@ -0,0 +1,56 @@
+package storage
+
+// RolloutFilter selects rollouts for ListRollouts. Zero fields match
+// everything.
+type RolloutFilter struct {
+	Tenant string
+	// States matches a rollout in any of them.
+	States []RolloutState
+	// Labels must all be present with these values.
+	Labels map[string]string
+}
+
+func (f RolloutFilter) Match(st *State) bool {
+	if f.Tenant != "" {
+		if tenant, _ := SplitKey(st.Key()); tenant != f.Tenant {
+			return false
+		}
+	}
+	if len(f.States) > 0 {
+		found := false
+		for _, s := range f.States {
+			if st.State == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for k, v := range f.Labels {
+		if got, ok := st.Labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// pageRollouts applies ListRollouts' filter and paging rules to states,
+// which must be in key order.
+func pageRollouts(states []*State, f RolloutFilter, after string, limit int64) ([]*State, string) {
+	if limit <= 0 {
+		return nil, ""
+	}
+	var out []*State
+	for _, st := range states {
+		if st.Key() <= after || !f.Match(st) {
+			continue
+		}
+		if int64(len(out)) == limit {
+			return out, out[len(out)-1].Key()
+		}
+		out = append(out, st)
+	}
+	return out, ""
+}
@@ -0,0 +1,427 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Memory is a Store that lives in process memory. It is meant for tests
+// and single-process experiments; nothing survives a restart.
+type Memory struct {
+	mu          sync.Mutex
+	states      map[string]State
+	policies    map[string]PolicyRecord
+	decisions   map[string]windowClaim
+	history     map[string][]HistoryEntry
+	seq         int64
+	retention   HistoryRetention
+	leases      map[string]lease
+	tokens      map[string]int64
+	checkpoints map[string][]byte
+	outbox      map[string][]OutboxMessage
+	notifier    Notifier
+	decisionBus Broadcaster
+}
+
+type windowClaim struct {
+	decision WindowDecision
+	expires  time.Time
+}
+
+type lease struct {
+	holder  string
+	token   int64
+	expires time.Time
+}
+
+var _ Store = (*Memory)(nil)
+
+func NewMemory() *Memory {
+	return &Memory{
+		states:      make(map[string]State),
+		policies:    make(map[string]PolicyRecord),
+		decisions:   make(map[string]windowClaim),
+		history:     make(map[string][]HistoryEntry),
+		leases:      make(map[string]lease),
+		tokens:      make(map[string]int64),
+		checkpoints: make(map[string][]byte),
+		outbox:      make(map[string][]OutboxMessage),
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (*State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st, ok := m.states[key]
+	if !ok {
+		return nil, nil
+	}
+	st.Approvals = append([]Approval(nil), st.Approvals...)
+	return &st, nil
+}
+
+func (m *Memory) ListRollouts(ctx context.Context, f RolloutFilter, after string, limit int64) ([]*State, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	states := make([]*State, 0, len(m.states))
+	for _, st := range m.states {
+		st := st
+		st.Approvals = append([]Approval(nil), st.Approvals...)
+		states = append(states, &st)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Key() < states[j].Key()
+	})
+	out, next := pageRollouts(states, f, after, limit)
+	return out, next, nil
+}
+
+func (m *Memory) Save(ctx context.Context, st *State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkFence(ctx); err != nil {
+		return err
+	}
+
+	cur := m.states[st.Key()]
+	if cur.Revision != st.Revision {
+		return &ConflictError{Key: st.Key(), Expected: st.Revision, Actual: cur.Revision}
+	}
+
+	for _, msg := range st.Outbox {
+		m.seq++
+		msg.ID = strconv.FormatInt(m.seq, 10)
+		m.outbox[st.Key()] = append(m.outbox[st.Key()], msg)
+	}
+	st.Outbox = nil
+	for i := range st.History {
+		m.appendHistory(&st.History[i])
+	}
+	st.History = nil
+
+	st.Revision++
+	st.LastUpdated = time.Now().UnixMilli()
+	next := *st
+	next.Approvals = append([]Approval(nil), st.Approvals...)
+	m.states[st.Key()] = next
+	return nil
+}
+
+func (m *Memory) ClaimWindow(
+	ctx context.Context,
+	key, windowID string,
+	d *WindowDecision,
+	ttl time.Duration,
+) (*WindowDecision, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	claim := key + ":" + windowID
+	now := time.Now()
+	if c, ok := m.decisions[claim]; ok && now.Before(c.expires) {
+		won := c.decision
+		return &won, false, nil
+	}
+	m.decisions[claim] = windowClaim{decision: *d, expires: now.Add(ttl)}
+	return d, true, nil
+}
+
+func (m *Memory) OutboxKeys(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.outbox))
+	for key := range m.outbox {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (m *Memory) PendingOutbox(ctx context.Context, key string, limit int64) ([]*OutboxMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []*OutboxMessage
+	for _, msg := range m.outbox[key] {
+		if int64(len(out)) == limit {
+			break
+		}
+		msg := msg
+		out = append(out, &msg)
+	}
+	return out, nil
+}
+
+func (m *Memory) AckOutbox(ctx context.Context, key, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msgs := m.outbox[key]
+	for i := range msgs {
+		if msgs[i].ID == id {
+			m.outbox[key] = append(msgs[:i:i], msgs[i+1:]...)
+			break
+		}
+	}
+	if len(m.outbox[key]) == 0 {
+		delete(m.outbox, key)
+	}
+	return nil
+}
+
+func (m *Memory) SetHistoryRetention(r HistoryRetention) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retention = r
+}
+
+func (m *Memory) AppendHistory(ctx context.Context, e *HistoryEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.appendHistory(e)
+	return nil
+}
+
+func (m *Memory) appendHistory(e *HistoryEntry) {
+	if e.Timestamp == 0 {
+		e.Timestamp = time.Now().UnixMilli()
+	}
+	m.seq++
+	e.ID = strconv.FormatInt(m.seq, 10)
+
+	entries := append(m.history[e.Key()], *e)
+	m.history[e.Key()] = trimHistory(entries, m.retention, e.Timestamp)
+}
+
+func (m *Memory) History(
+	ctx context.Context,
+	key string,
+	from, to int64,
+	after string,
+	limit int64,
+) ([]*HistoryEntry, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return pageHistory(m.history[key], from, to, after, limit)
+}
+
+func (m *Memory) DecisionHistory(ctx context.Context, key string, from, limit int64) ([]*HistoryEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []*HistoryEntry
+	for i := range m.history[key] {
+		e := m.history[key][i]
+		if e.Sequence == 0 || e.Sequence < from {
+			continue
+		}
+		if len(out) == 0 && e.Sequence != from {
+			return nil, ErrHistoryTrimmed
+		}
+		if int64(len(out)) == limit {
+			break
+		}
+		out = append(out, &e)
+	}
+	return out, nil
+}
+
+func (m *Memory) PutPolicy(ctx context.Context, key, spec string, expectedVersion int64) (*PolicyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.policies[key].Version != expectedVersion {
+		return nil, ErrVersionConflict
+	}
+	tenant, service := SplitKey(key)
+	rec := PolicyRecord{
+		Tenant:    tenant,
+		ServiceID: service,
+		Version:   expectedVersion + 1,
+		Spec:      spec,
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+	m.policies[key] = rec
+	m.notifier.Notify(key)
+	return &rec, nil
+}
+
+func (m *Memory) GetPolicy(ctx context.Context, key string) (*PolicyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.policies[key]
+	if !ok {
+		return nil, nil
+	}
+	return &rec, nil
+}
+
+func (m *Memory) ListPolicies(ctx context.Context) ([]*PolicyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records := make([]*PolicyRecord, 0, len(m.policies))
+	for _, rec := range m.policies {
+		rec := rec
+		records = append(records, &rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key() < records[j].Key()
+	})
+	return records, nil
+}
+
+func (m *Memory) DeletePolicy(ctx context.Context, key string, expectedVersion int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.policies[key]
+	if !ok {
+		return false, nil
+	}
+	if rec.Version != expectedVersion {
+		return false, ErrVersionConflict
+	}
+	delete(m.policies, key)
+	m.notifier.Notify(key)
+	return true, nil
+}
+
+func (m *Memory) SubscribePolicies(ctx context.Context) (<-chan string, error) {
+	return m.notifier.Subscribe(ctx), nil
+}
+
+func (m *Memory) PublishDecision(ctx context.Context, payload []byte) error {
+	m.decisionBus.Broadcast(payload)
+	return nil
+}
+
+func (m *Memory) SubscribeDecisions(ctx context.Context) (<-chan []byte, error) {
+	return m.decisionBus.Subscribe(ctx), nil
+}
+
+func (m *Memory) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	l := m.leases[name]
+	switch {
+	case l.holder == holder && now.Before(l.expires):
+	case l.holder == "" || !now.Before(l.expires):
+		m.tokens[name]++
+		l = lease{holder: holder, token: m.tokens[name]}
+	default:
+		return 0, nil
+	}
+	l.expires = now.Add(ttl)
+	m.leases[name] = l
+	return l.token, nil
+}
+
+// checkFence fails with ErrFenced if ctx carries a stale token. Callers
+// hold m.mu.
+func (m *Memory) checkFence(ctx context.Context) error {
+	if f, ok := FenceFrom(ctx); ok {
+		l := m.leases[f.Lease]
+		if l.token != f.Token || time.Now().After(l.expires) {
+			return ErrFenced
+		}
+	}
+	return nil
+}
+
+func (m *Memory) SaveCheckpoint(ctx context.Context, name string, cp *Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkFence(ctx); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	m.checkpoints[name] = bytes
+	return nil
+}
+
+func (m *Memory) LoadCheckpoint(ctx context.Context, name string) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bytes, ok := m.checkpoints[name]
+	if !ok {
+		return nil, nil
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(bytes, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (m *Memory) ReleaseLease(ctx context.Context, name, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.leases[name].holder == holder {
+		delete(m.leases, name)
+	}
+	return nil
+}
+
+// trimHistory drops the oldest entries that fall outside r, as of now
+// (unix ms). entries must be oldest first.
+func trimHistory(entries []HistoryEntry, r HistoryRetention, now int64) []HistoryEntry {
+	if r.MaxAge > 0 {
+		cutoff := now - r.MaxAge.Milliseconds()
+		i := sort.Search(len(entries), func(i int) bool {
+			return entries[i].Timestamp >= cutoff
+		})
+		entries = entries[i:]
+	}
+	if r.MaxLen > 0 && int64(len(entries)) > r.MaxLen {
+		entries = entries[int64(len(entries))-r.MaxLen:]
+	}
+	return entries
+}
+
+// pageHistory applies History's range and paging rules to entries, which
+// must be oldest first with numeric IDs.
+func pageHistory(entries []HistoryEntry, from, to int64, after string, limit int64) ([]*HistoryEntry, string, error) {
+	var afterSeq int64
+	if after != "" {
+		var err error
+		if afterSeq, err = strconv.ParseInt(after, 10, 64); err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+	}
+
+	var out []*HistoryEntry
+	for i := range entries {
+		e := entries[i]
+		if seq, _ := strconv.ParseInt(e.ID, 10, 64); seq <= afterSeq {
+			continue
+		}
+		if (from > 0 && e.Timestamp < from) || (to > 0 && e.Timestamp > to) {
+			continue
+		}
+		if int64(len(out)) == limit {
+			return out, out[len(out)-1].ID, nil
+		}
+		out = append(out, &e)
+	}
+	return out, "", nil
+}
@@ -0,0 +1,337 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSaveRejectsStaleRevision(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	st := &State{ServiceID: "checkout-service", State: Canary}
+	if err := m.Save(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Revision != 1 {
+		t.Fatalf("expected revision 1 after first save, got %d", st.Revision)
+	}
+
+	stale := &State{ServiceID: "checkout-service", State: Paused}
+	err := m.Save(ctx, stale)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if conflict.Expected != 0 || conflict.Actual != 1 || stale.Revision != 0 {
+		t.Fatalf("unexpected conflict %+v (state revision %d)", conflict, stale.Revision)
+	}
+}
+
+func TestUpdateRetriesOnConflict(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	m.Save(ctx, &State{ServiceID: "checkout-service", TrafficWeight: 10})
+
+	calls := 0
+	st, err := Update(ctx, m, "default/checkout-service", func(cur *State) (*State, error) {
+		calls++
+		if calls == 1 {
+			// Someone else writes between our read and our save.
+			m.Save(ctx, &State{ServiceID: "checkout-service", Revision: 1, TrafficWeight: 25})
+		}
+		cur.TrafficWeight += 5
+		return cur, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || st.TrafficWeight != 30 || st.Revision != 3 {
+		t.Fatalf("expected a retry on fresh state, got calls=%d weight=%d revision=%d",
+			calls, st.TrafficWeight, st.Revision)
+	}
+}
+
+func TestTenantsDoNotShareRollouts(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	acme := &State{Tenant: "acme", ServiceID: "api", TrafficWeight: 10}
+	globex := &State{Tenant: "globex", ServiceID: "api", TrafficWeight: 50}
+	if err := m.Save(ctx, acme); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(ctx, globex); err != nil {
+		t.Fatalf("second tenant's first save conflicted: %v", err)
+	}
+
+	got, _ := m.Get(ctx, Key("acme", "api"))
+	if got == nil || got.TrafficWeight != 10 {
+		t.Fatalf("acme's rollout was overwritten: %+v", got)
+	}
+	if got, _ := m.Get(ctx, Key("", "api")); got != nil {
+		t.Fatalf("default tenant sees another tenant's rollout: %+v", got)
+	}
+
+	rec, _ := m.PutPolicy(ctx, Key("acme", "api"), "service: api", 0)
+	if rec.Tenant != "acme" || rec.ServiceID != "api" {
+		t.Fatalf("policy record not split into tenant and service: %+v", rec)
+	}
+}
+
+func TestHistoryPagesAndTrims(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	m.SetHistoryRetention(HistoryRetention{MaxLen: 4})
+
+	for i := 0; i < 6; i++ {
+		m.AppendHistory(ctx, &HistoryEntry{
+			ServiceID: "checkout-service",
+			Kind:      HistoryDecision,
+			Timestamp: int64(1000 + i),
+		})
+	}
+
+	page, next, err := m.History(ctx, "default/checkout-service", 0, 0, "", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 3 || page[0].Timestamp != 1002 || next == "" {
+		t.Fatalf("expected the 3 oldest retained entries and a token, got %d (next %q)", len(page), next)
+	}
+
+	page, next, _ = m.History(ctx, "default/checkout-service", 0, 0, next, 3)
+	if len(page) != 1 || page[0].Timestamp != 1005 || next != "" {
+		t.Fatalf("expected the last entry and no token, got %d (next %q)", len(page), next)
+	}
+
+	page, _, _ = m.History(ctx, "default/checkout-service", 1003, 1004, "", 10)
+	if len(page) != 2 {
+		t.Fatalf("expected 2 entries in range, got %d", len(page))
+	}
+
+	if _, _, err := m.History(ctx, "default/checkout-service", 0, 0, "bogus", 10); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("expected invalid token error, got %v", err)
+	}
+}
+
+func TestPolicyUpdatesReachSubscribers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemory()
+
+	updates, _ := m.SubscribePolicies(ctx)
+	if _, err := m.PutPolicy(ctx, "default/checkout-service", "service: checkout-service", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.PutPolicy(ctx, "default/checkout-service", "service: checkout-service", 0); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+
+	select {
+	case id := <-updates:
+		if id != "default/checkout-service" {
+			t.Fatalf("unexpected update for %q", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no update delivered")
+	}
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestFencedSaveRejectedAfterTakeover(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	first, _ := m.AcquireLease(ctx, "engine", "a", 20*time.Millisecond)
+	if first == 0 {
+		t.Fatal("expected a to win the free lease")
+	}
+	if tok, _ := m.AcquireLease(ctx, "engine", "b", time.Second); tok != 0 {
+		t.Fatalf("b must not take a live lease, got token %d", tok)
+	}
+	if err := m.Save(WithFence(ctx, "engine", first), &State{ServiceID: "checkout-service"}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	second, _ := m.AcquireLease(ctx, "engine", "b", time.Second)
+	if second <= first {
+		t.Fatalf("expected b to take over with a larger token, got %d after %d", second, first)
+	}
+
+	st := &State{ServiceID: "checkout-service", Revision: 1}
+	if err := m.Save(WithFence(ctx, "engine", first), st); !errors.Is(err, ErrFenced) {
+		t.Fatalf("expected the deposed leader's write to be fenced, got %v", err)
+	}
+	if err := m.Save(WithFence(ctx, "engine", second), st); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckpointRoundTripIsFenced(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	token, _ := m.AcquireLease(ctx, "engine", "a", time.Minute)
+	cp := &Checkpoint{
+		Offsets: map[int]int64{0: 42},
+		Windows: map[string]json.RawMessage{"checkout-service": json.RawMessage(`[{"LatencyMs":12}]`)},
+	}
+	if err := m.SaveCheckpoint(WithFence(ctx, "engine", token), "engine", cp); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SaveCheckpoint(WithFence(ctx, "engine", token+1), "engine", cp); !errors.Is(err, ErrFenced) {
+		t.Fatalf("expected a foreign token to be fenced, got %v", err)
+	}
+
+	got, err := m.LoadCheckpoint(ctx, "engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Offsets[0] != 42 || string(got.Windows["checkout-service"]) != `[{"LatencyMs":12}]` {
+		t.Fatalf("checkpoint did not round-trip: %+v", got)
+	}
+}
+
+func TestDecisionsReachEverySubscriber(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemory()
+
+	first, _ := m.SubscribeDecisions(ctx)
+	second, _ := m.SubscribeDecisions(ctx)
+	m.PublishDecision(ctx, []byte("one"))
+	m.PublishDecision(ctx, []byte("two"))
+
+	for _, sub := range []<-chan []byte{first, second} {
+		for _, want := range []string{"one", "two"} {
+			select {
+			case got := <-sub:
+				if string(got) != want {
+					t.Fatalf("expected %q, got %q", want, got)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("subscriber missed %q", want)
+			}
+		}
+	}
+
+	cancel()
+	for range first {
+	}
+}
+
+func TestOutboxIsWrittenWithTheState(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	key := Key("", "checkout-service")
+
+	st := &State{ServiceID: "checkout-service", Outbox: []OutboxMessage{{DecisionID: "d1", Payload: []byte("one")}}}
+	if err := m.Save(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Outbox != nil {
+		t.Fatal("a successful save must clear the outbox")
+	}
+
+	stale := &State{ServiceID: "checkout-service", Outbox: []OutboxMessage{{DecisionID: "d2"}}}
+	if err := m.Save(ctx, stale); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	msgs, _ := m.PendingOutbox(ctx, key, 10)
+	if len(msgs) != 1 || msgs[0].DecisionID != "d1" || string(msgs[0].Payload) != "one" {
+		t.Fatalf("expected only the saved decision to be pending, got %+v", msgs)
+	}
+
+	if err := m.AckOutbox(ctx, key, msgs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if msgs, _ := m.PendingOutbox(ctx, key, 10); len(msgs) != 0 {
+		t.Fatalf("acknowledged message still pending: %+v", msgs)
+	}
+}
+
+func TestDecisionHistoryIsWrittenWithTheStateAndTrimmed(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	m.SetHistoryRetention(HistoryRetention{MaxLen: 3})
+	key := Key("", "checkout-service")
+
+	st := &State{ServiceID: "checkout-service"}
+	for seq := int64(1); seq <= 4; seq++ {
+		st.Sequence = seq
+		st.History = []HistoryEntry{{ServiceID: "checkout-service", Sequence: seq}}
+		if err := m.Save(ctx, st); err != nil {
+			t.Fatal(err)
+		}
+		if st.History != nil {
+			t.Fatal("a successful save must clear the history")
+		}
+		if seq == 2 {
+			// Entries announcing nothing are not decisions.
+			m.AppendHistory(ctx, &HistoryEntry{ServiceID: "checkout-service", Kind: HistoryApproval})
+		}
+	}
+
+	stale := &State{ServiceID: "checkout-service", History: []HistoryEntry{{ServiceID: "checkout-service", Sequence: 5}}}
+	if err := m.Save(ctx, stale); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	entries, err := m.DecisionHistory(ctx, key, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Sequence != 3 || entries[1].Sequence != 4 {
+		t.Fatalf("expected decisions 3 and 4, got %+v", entries)
+	}
+	if entries, _ := m.DecisionHistory(ctx, key, 5, 10); len(entries) != 0 {
+		t.Fatalf("expected nothing after the last decision, got %+v", entries)
+	}
+	if _, err := m.DecisionHistory(ctx, key, 2, 10); !errors.Is(err, ErrHistoryTrimmed) {
+		t.Fatalf("expected decision 2 to be trimmed, got %v", err)
+	}
+}
+
+func TestListRolloutsFiltersAndPages(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	for _, st := range []*State{
+		{Tenant: "acme", ServiceID: "api", State: Canary, Labels: map[string]string{"team": "core"}},
+		{Tenant: "acme", ServiceID: "web", State: Canary, Labels: map[string]string{"team": "edge"}},
+		{Tenant: "acme", ServiceID: "worker", State: Promoted, Labels: map[string]string{"team": "core"}},
+		{Tenant: "globex", ServiceID: "api", State: Canary, Labels: map[string]string{"team": "core"}},
+	} {
+		if err := m.Save(ctx, st); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f := RolloutFilter{Tenant: "acme", Labels: map[string]string{"team": "core"}}
+	page, next, _ := m.ListRollouts(ctx, f, "", 1)
+	if len(page) != 1 || page[0].ServiceID != "api" || next == "" {
+		t.Fatalf("expected acme/api and a next page, got %d (next %q)", len(page), next)
+	}
+	page, next, _ = m.ListRollouts(ctx, f, next, 1)
+	if len(page) != 1 || page[0].ServiceID != "worker" || next != "" {
+		t.Fatalf("expected acme/worker and no next page, got %d (next %q)", len(page), next)
+	}
+
+	f.States = []RolloutState{Canary}
+	if page, _, _ := m.ListRollouts(ctx, f, "", 10); len(page) != 1 || page[0].ServiceID != "api" {
+		t.Fatalf("expected only acme/api in CANARY, got %d", len(page))
+	}
+
+	if page, next, err := m.ListRollouts(ctx, RolloutFilter{}, "", 0); err != nil || len(page) != 0 || next != "" {
+		t.Fatalf("expected an empty page for limit 0, got %d (next %q, err %v)", len(page), next, err)
+	}
+}
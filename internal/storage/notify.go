@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+// Notifier fans policy-change notifications out to in-process
+// subscribers, for backends without a pub/sub of their own. Slow
+// subscribers never block Notify and never miss a service: pending IDs
+// are queued per subscriber and de-duplicated.
+type Notifier struct {
+	mu   sync.Mutex
+	subs map[*subscription]struct{}
+}
+
+type subscription struct {
+	mu      sync.Mutex
+	pending []string
+	wake    chan struct{}
+}
+
+// Subscribe returns a channel of changed service IDs, closed when ctx
+// is done.
+func (n *Notifier) Subscribe(ctx context.Context) <-chan string {
+	sub := &subscription{wake: make(chan struct{}, 1)}
+
+	n.mu.Lock()
+	if n.subs == nil {
+		n.subs = make(map[*subscription]struct{})
+	}
+	n.subs[sub] = struct{}{}
+	n.mu.Unlock()
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer func() {
+			n.mu.Lock()
+			delete(n.subs, sub)
+			n.mu.Unlock()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.wake:
+			}
+
+			sub.mu.Lock()
+			ids := sub.pending
+			sub.pending = nil
+			sub.mu.Unlock()
+
+			for _, id := range ids {
+				select {
+				case out <- id:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func (n *Notifier) Notify(serviceID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for sub := range n.subs {
+		sub.mu.Lock()
+		queued := false
+		for _, id := range sub.pending {
+			if id == serviceID {
+				queued = true
+				break
+			}
+		}
+		if !queued {
+			sub.pending = append(sub.pending, serviceID)
+		}
+		sub.mu.Unlock()
+
+		select {
+		case sub.wake <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package storage
+
+import "context"
+
+// OutboxMessage is a decision waiting to be delivered. It is written in
+// the same atomic Save as the state change that produced it, so the two
+// can never disagree; a relay delivers it afterwards.
+type OutboxMessage struct {
+	// ID is assigned on append and orders a service's messages.
+	ID string `json:"-"`
+	// DecisionID is stable across redeliveries so consumers can drop
+	// duplicates.
+	DecisionID string `json:"decision_id"`
+	Payload    []byte `json:"payload"`
+}
+
+// OutboxStore hands undelivered messages to the relay. Delivery is at
+// least once: a message stays pending until acknowledged.
+type OutboxStore interface {
+	// OutboxKeys lists the services that may have pending messages.
+	OutboxKeys(ctx context.Context) ([]string, error)
+	// PendingOutbox returns up to limit of key's undelivered messages,
+	// oldest first.
+	PendingOutbox(ctx context.Context, key string, limit int64) ([]*OutboxMessage, error)
+	AckOutbox(ctx context.Context, key, id string) error
+}
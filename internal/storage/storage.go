@@ -0,0 +1,297 @@
+// Package storage defines the control plane's persisted types and the
+// interfaces its backends (Redis, bbolt, in-memory) implement.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type RolloutState string
+
+const (
+	Canary           RolloutState = "CANARY"
+	Promoted         RolloutState = "PROMOTED"
+	Paused           RolloutState = "PAUSED"
+	RolledBack       RolloutState = "ROLLED_BACK"
+	AwaitingApproval RolloutState = "AWAITING_APPROVAL"
+)
+
+type State struct {
+	// RolloutID identifies this rollout; the next rollout of the same
+	// service replaces the record under a new ID.
+	RolloutID string `json:"rollout_id,omitempty"`
+	Tenant    string `json:"tenant,omitempty"`
+	ServiceID string `json:"service_id"`
+	// Revision increases by one on every successful Save; a Save only
+	// succeeds if the stored revision still equals this one.
+	Revision int64 `json:"revision"`
+	// Version is being rolled out in place of BaselineVersion.
+	Version         string            `json:"version"`
+	BaselineVersion string            `json:"baseline_version,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	StartedAt       int64             `json:"started_at,omitempty"`
+	// FinishedAt is set when the rollout reaches a terminal state.
+	FinishedAt int64 `json:"finished_at,omitempty"`
+	// Window is the policy window whose decision was applied last.
+	Window string `json:"window,omitempty"`
+	// DecidedThrough is the newest event time (unix ms) in the batches
+	// decided so far; events up to it are not judged again.
+	DecidedThrough int64        `json:"decided_through,omitempty"`
+	State          RolloutState `json:"state"`
+	Step           int          `json:"step"`
+	TrafficWeight  int          `json:"traffic_weight"`
+	Approvals      []Approval   `json:"approvals,omitempty"`
+	LastDecision   string       `json:"last_decision"`
+	// Sequence numbers the service's DecisionEvents: each one takes the
+	// next value, and it carries over from one rollout to the next.
+	Sequence int64 `json:"sequence,omitempty"`
+	// Override is the manual action holding the rollout, if any.
+	Override *Override `json:"override,omitempty"`
+	// LastVerdict is the evaluation behind the latest window decision.
+	LastVerdict *VerdictRecord `json:"last_verdict,omitempty"`
+	LastUpdated int64          `json:"last_updated"`
+
+	// Outbox is appended to the service's outbox by the Save that writes
+	// this state, atomically with it. It is not part of the stored state
+	// and is cleared by a successful Save.
+	Outbox []OutboxMessage `json:"-"`
+	// History is appended to the service's history by the same Save,
+	// so an entry announced by an Outbox message is never missing. Like
+	// Outbox it is not stored with the state and is cleared by a
+	// successful Save.
+	History []HistoryEntry `json:"-"`
+}
+
+// OverrideAction is a manual action an operator takes on a rollout.
+type OverrideAction string
+
+const (
+	OverridePause   OverrideAction = "PAUSE"
+	OverrideResume  OverrideAction = "RESUME"
+	OverrideAbort   OverrideAction = "ABORT"
+	OverridePromote OverrideAction = "PROMOTE"
+)
+
+// Override records a manual action. A pause stays on the rollout and
+// holds off automated verdicts until it expires (ExpiresAt, unix ms; 0
+// never) or is cleared by another action.
+type Override struct {
+	Action    OverrideAction `json:"action"`
+	Actor     string         `json:"actor"`
+	Reason    string         `json:"reason"`
+	SetAt     int64          `json:"set_at"`
+	ExpiresAt int64          `json:"expires_at,omitempty"`
+}
+
+// VerdictRecord is a window's verdict, the decision taken on it (they
+// differ when a gate holds the rollout) and the metrics behind it.
+type VerdictRecord struct {
+	Verdict   string             `json:"verdict"`
+	Decision  string             `json:"decision"`
+	Reason    string             `json:"reason"`
+	Metrics   map[string]float64 `json:"metrics,omitempty"`
+	DecidedAt int64              `json:"decided_at"`
+}
+
+// WindowDecision is the decision recorded for a policy window, so a
+// replay of the window gets the same answer.
+type WindowDecision struct {
+	Decision  string `json:"decision"`
+	Reason    string `json:"reason"`
+	DecidedAt int64  `json:"decided_at"`
+}
+
+type Approval struct {
+	Approver   string `json:"approver"`
+	Step       int    `json:"step"`
+	Comment    string `json:"comment,omitempty"`
+	ApprovedAt int64  `json:"approved_at"`
+}
+
+type PolicyRecord struct {
+	Tenant    string `json:"tenant,omitempty"`
+	ServiceID string `json:"service_id"`
+	Version   int64  `json:"version"`
+	Spec      string `json:"spec"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// HistoryKind says what produced a history entry.
+type HistoryKind string
+
+const (
+	HistoryStart    HistoryKind = "START"
+	HistoryDecision HistoryKind = "DECISION"
+	HistoryApproval HistoryKind = "APPROVAL"
+	// HistoryManual entries carry the OverrideAction as Decision.
+	HistoryManual HistoryKind = "MANUAL"
+)
+
+// HistoryEntry is one line of a rollout's audit log. From and To are equal
+// when the action did not change the rollout state.
+type HistoryEntry struct {
+	// ID is assigned on append and orders entries within a service.
+	ID            string             `json:"-"`
+	RolloutID     string             `json:"rollout_id,omitempty"`
+	Tenant        string             `json:"tenant,omitempty"`
+	ServiceID     string             `json:"service_id"`
+	Kind          HistoryKind        `json:"kind"`
+	Actor         string             `json:"actor"`
+	From          RolloutState       `json:"from,omitempty"`
+	To            RolloutState       `json:"to"`
+	Verdict       string             `json:"verdict,omitempty"`
+	Decision      string             `json:"decision,omitempty"`
+	Reason        string             `json:"reason,omitempty"`
+	Comment       string             `json:"comment,omitempty"`
+	PolicyVersion int64              `json:"policy_version"`
+	TrafficWeight int                `json:"traffic_weight"`
+	Metrics       map[string]float64 `json:"metrics,omitempty"`
+	Timestamp     int64              `json:"timestamp"`
+	// DecisionID and Sequence identify the DecisionEvent announcing the
+	// entry; both are empty for entries that announce nothing.
+	DecisionID string `json:"decision_id,omitempty"`
+	Sequence   int64  `json:"sequence,omitempty"`
+}
+
+// HistoryRetention bounds each service's history. Zero values keep
+// everything.
+type HistoryRetention struct {
+	MaxAge time.Duration
+	MaxLen int64
+}
+
+// ErrVersionConflict is returned when a write carries a stale expected
+// version or revision.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrInvalidPageToken is returned by History for a token it did not issue.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ErrHistoryTrimmed is returned by DecisionHistory when the first decision
+// asked for has been trimmed by the history retention.
+var ErrHistoryTrimmed = errors.New("decision history trimmed")
+
+// ConflictError is returned by Save when the stored revision moved
+// on since the state was read. Re-read, re-apply and retry.
+type ConflictError struct {
+	Key      string
+	Expected int64
+	Actual   int64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("rollout %s: revision conflict (expected %d, stored %d)",
+		e.Key, e.Expected, e.Actual)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// StateStore persists rollout state, decision idempotency keys and the
+// rollout audit log. Services are addressed by their tenant-qualified
+// Key.
+type StateStore interface {
+	// Get returns nil, nil when the service has no rollout.
+	Get(ctx context.Context, key string) (*State, error)
+	// Save writes st if the stored revision still equals st.Revision
+	// (0 = none stored) and bumps st.Revision. Otherwise it returns a
+	// *ConflictError and leaves st unchanged. If ctx carries a Fence the
+	// write also fails with ErrFenced once that token is stale. st.Outbox
+	// and st.History are written together with the state or not at all.
+	Save(ctx context.Context, st *State) error
+	// ClaimWindow records d as the decision for windowID unless one is
+	// already recorded. It returns the recorded decision and whether d
+	// won. The record expires after ttl.
+	ClaimWindow(ctx context.Context, key, windowID string, d *WindowDecision, ttl time.Duration) (*WindowDecision, bool, error)
+
+	AppendHistory(ctx context.Context, e *HistoryEntry) error
+	// History returns up to limit entries recorded within [from, to]
+	// (unix ms, 0 = unbounded), oldest first. after resumes from a
+	// previous page's last entry ID; next is empty once the range is
+	// exhausted.
+	History(ctx context.Context, key string, from, to int64, after string, limit int64) (entries []*HistoryEntry, next string, err error)
+	// DecisionHistory returns up to limit of the entries announcing the
+	// service's DecisionEvents numbered from and up, in sequence order,
+	// without reading the entries before them. It returns
+	// ErrHistoryTrimmed if decision from is no longer retained.
+	DecisionHistory(ctx context.Context, key string, from, limit int64) ([]*HistoryEntry, error)
+	SetHistoryRetention(r HistoryRetention)
+
+	// ListRollouts returns up to limit rollouts matching f in key order,
+	// resuming after the key after (empty for the first page). next is
+	// empty once there are no more. A limit of zero or less returns
+	// nothing.
+	ListRollouts(ctx context.Context, f RolloutFilter, after string, limit int64) (rollouts []*State, next string, err error)
+}
+
+// PolicyStore persists versioned policies and announces changes. Like
+// StateStore it addresses services by Key.
+type PolicyStore interface {
+	// PutPolicy stores spec as the next version of the service's policy.
+	// expectedVersion must match the stored version (0 to create).
+	PutPolicy(ctx context.Context, key, spec string, expectedVersion int64) (*PolicyRecord, error)
+	// GetPolicy returns nil, nil when the service has no policy.
+	GetPolicy(ctx context.Context, key string) (*PolicyRecord, error)
+	ListPolicies(ctx context.Context) ([]*PolicyRecord, error)
+	// DeletePolicy removes the policy if it is still at expectedVersion.
+	// It reports false when there was nothing to delete.
+	DeletePolicy(ctx context.Context, key string, expectedVersion int64) (bool, error)
+	// SubscribePolicies streams the keys of services whose policy changed,
+	// and ResyncPolicies when changes may have been missed. The channel is
+	// closed when ctx is done.
+	SubscribePolicies(ctx context.Context) (<-chan string, error)
+}
+
+// ResyncPolicies is sent by SubscribePolicies in place of a key after a
+// reconnect, when notifications published meanwhile are lost: the reader
+// should re-list every policy.
+const ResyncPolicies = ""
+
+// Store is everything the control plane persists.
+type Store interface {
+	StateStore
+	PolicyStore
+	LeaseStore
+	CheckpointStore
+	DecisionBus
+	OutboxStore
+}
+
+// maxUpdateAttempts bounds Update's retries under contention.
+const maxUpdateAttempts = 5
+
+// Update runs a read-modify-write on a rollout's state. fn receives the
+// current state (nil if none) and returns the state to save, or nil to
+// leave it alone. On a revision conflict fn is re-run on fresh state.
+func Update(
+	ctx context.Context,
+	s StateStore,
+	key string,
+	fn func(cur *State) (*State, error),
+) (*State, error) {
+	var err error
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var cur, next *State
+		if cur, err = s.Get(ctx, key); err != nil {
+			return nil, err
+		}
+		if next, err = fn(cur); err != nil || next == nil {
+			return cur, err
+		}
+
+		if cur != nil {
+			next.Revision = cur.Revision
+		} else {
+			next.Revision = 0
+		}
+		err = s.Save(ctx, next)
+		if !errors.Is(err, ErrVersionConflict) {
+			return next, err
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultTenant owns rollouts and policies that name no tenant.
+const DefaultTenant = "default"
+
+// Rollouts, windows, history and policies are stored under a key that
+// qualifies the service with its tenant, so two tenants may both run a
+// service called "api" without sharing any record.
+
+// Key returns the store key of a tenant's service. An empty tenant is
+// DefaultTenant.
+func Key(tenant, service string) string {
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+	return tenant + "/" + service
+}
+
+// SplitKey is the inverse of Key.
+func SplitKey(key string) (tenant, service string) {
+	tenant, service, ok := strings.Cut(key, "/")
+	if !ok {
+		return DefaultTenant, key
+	}
+	return tenant, service
+}
+
+// ValidateTenant rejects names that cannot be used as a key prefix.
+// Empty is allowed and means DefaultTenant.
+func ValidateTenant(tenant string) error {
+	if strings.ContainsAny(tenant, "/:{} ") {
+		return fmt.Errorf("tenant %q must not contain '/', ':', '{', '}' or spaces", tenant)
+	}
+	return nil
+}
+
+func (s *State) Key() string {
+	return Key(s.Tenant, s.ServiceID)
+}
+
+func (e *HistoryEntry) Key() string {
+	return Key(e.Tenant, e.ServiceID)
+}
+
+func (r *PolicyRecord) Key() string {
+	return Key(r.Tenant, r.ServiceID)
+}
@@ -22,6 +22,21 @@ func main() {
 
 	client := rolloutpb.NewRolloutControlClient(conn)
 
+	started, err := client.StartRollout(context.Background(), &rolloutpb.StartRolloutRequest{
+		ServiceId:       "checkout-service",
+		Version:         "v2",
+		BaselineVersion: "v1",
+		Actor:           "grpc-client",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if started.Accepted {
+		log.Printf("STARTED ROLLOUT: %s", started.RolloutId)
+	} else {
+		log.Printf("NOT STARTED: %s", started.RejectionReason)
+	}
+
 	stream, err := client.StreamDecisions(
 		context.Background(),
 		&rolloutpb.StreamDecisionsRequest{ServiceId: "checkout-service"},